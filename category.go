@@ -0,0 +1,66 @@
+package schema
+
+import (
+	"reflect"
+	"time"
+)
+
+// Category is a coarse classification of a field's type for dispatching in serializers.
+type Category string
+
+const (
+	// CategoryString covers string kinds.
+	CategoryString Category = "string"
+	// CategoryNumeric covers all int, uint and float kinds.
+	CategoryNumeric Category = "numeric"
+	// CategoryBool covers bool kinds.
+	CategoryBool Category = "bool"
+	// CategoryTime covers time.Time.
+	CategoryTime Category = "time"
+	// CategoryStruct covers struct kinds other than time.Time.
+	CategoryStruct Category = "struct"
+	// CategorySlice covers slice and array kinds.
+	CategorySlice Category = "slice"
+	// CategoryMap covers map kinds.
+	CategoryMap Category = "map"
+	// CategoryOther covers everything else (complex numbers, channels, funcs, interfaces, ...).
+	CategoryOther Category = "other"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Category returns the category of the field's type, unwrapping pointers (so *int is CategoryNumeric).
+func (f FieldMetadata) Category() Category {
+	if f.Type == nil {
+		return CategoryOther
+	}
+
+	return categoryOf(derefType(f.Type))
+}
+
+// categoryOf classifies a non-pointer type.
+func categoryOf(typ reflect.Type) Category {
+	if typ == timeType {
+		return CategoryTime
+	}
+
+	//nolint:exhaustive // Remaining kinds fall into CategoryOther
+	switch typ.Kind() {
+	case reflect.String:
+		return CategoryString
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return CategoryNumeric
+	case reflect.Bool:
+		return CategoryBool
+	case reflect.Struct:
+		return CategoryStruct
+	case reflect.Slice, reflect.Array:
+		return CategorySlice
+	case reflect.Map:
+		return CategoryMap
+	default:
+		return CategoryOther
+	}
+}
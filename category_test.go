@@ -0,0 +1,63 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldMetadata_Category(t *testing.T) {
+	type myString string
+
+	tests := []struct {
+		name string
+		typ  reflect.Type
+		want Category
+	}{
+		{name: "string", typ: reflect.TypeOf(""), want: CategoryString},
+		{name: "named string", typ: reflect.TypeOf(myString("")), want: CategoryString},
+		{name: "int", typ: reflect.TypeOf(0), want: CategoryNumeric},
+		{name: "int8", typ: reflect.TypeOf(int8(0)), want: CategoryNumeric},
+		{name: "int64", typ: reflect.TypeOf(int64(0)), want: CategoryNumeric},
+		{name: "uint", typ: reflect.TypeOf(uint(0)), want: CategoryNumeric},
+		{name: "uint32", typ: reflect.TypeOf(uint32(0)), want: CategoryNumeric},
+		{name: "float32", typ: reflect.TypeOf(float32(0)), want: CategoryNumeric},
+		{name: "float64", typ: reflect.TypeOf(float64(0)), want: CategoryNumeric},
+		{name: "pointer to int", typ: reflect.TypeOf((*int)(nil)), want: CategoryNumeric},
+		{name: "double pointer to string", typ: reflect.TypeOf((**string)(nil)), want: CategoryString},
+		{name: "bool", typ: reflect.TypeOf(false), want: CategoryBool},
+		{name: "time", typ: reflect.TypeOf(time.Time{}), want: CategoryTime},
+		{name: "pointer to time", typ: reflect.TypeOf(&time.Time{}), want: CategoryTime},
+		{name: "struct", typ: reflect.TypeOf(struct{ Name string }{}), want: CategoryStruct},
+		{name: "slice", typ: reflect.TypeOf([]string{}), want: CategorySlice},
+		{name: "array", typ: reflect.TypeOf([4]byte{}), want: CategorySlice},
+		{name: "map", typ: reflect.TypeOf(map[string]int{}), want: CategoryMap},
+		{name: "complex", typ: reflect.TypeOf(complex64(0)), want: CategoryOther},
+		{name: "channel", typ: reflect.TypeOf(make(chan int)), want: CategoryOther},
+		{name: "func", typ: reflect.TypeOf(func() {}), want: CategoryOther},
+		{name: "empty interface", typ: reflect.TypeFor[any](), want: CategoryOther},
+		{name: "named interface", typ: reflect.TypeFor[fmt.Stringer](), want: CategoryOther},
+		{name: "nil type", typ: nil, want: CategoryOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := FieldMetadata{StructFieldName: "Field", Type: tt.typ}
+			assert.Equal(t, tt.want, field.Category())
+		})
+	}
+
+	t.Run("field returned by MustFieldByName", func(t *testing.T) {
+		type event struct {
+			At *time.Time
+		}
+		structMeta, err := NewDefaultMetadata().Parse(event{})
+		require.NoError(t, err)
+
+		assert.Equal(t, CategoryTime, structMeta.MustFieldByName("At").Category())
+	})
+}
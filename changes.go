@@ -0,0 +1,56 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ChangedColumns returns the resolved column names of the fields whose values differ between oldVal
+// and newVal, in declaration order, for building partial UPDATE statements. Both must be structs or
+// pointers to structs of the same struct type; the metadata is parsed once, from the cache. The
+// fields compared are those ToMap converts (ignored, transient and unexported fields are skipped); intersect the
+// result with UpdatableFields to leave out read-only and primary key columns. Values are compared
+// with reflect.DeepEqual after dereferencing pointers, so two nil pointers, or a field behind a nil
+// embedded pointer in both values, are equal, while nil and a pointer to a zero value are not.
+func (m *Metadata) ChangedColumns(oldVal, newVal any) ([]string, error) {
+	oldRV, err := resolveStructRoot(oldVal, "compare %s", false)
+	if err != nil {
+		return nil, err
+	}
+	newRV, err := resolveStructRoot(newVal, "compare %s", false)
+	if err != nil {
+		return nil, err
+	}
+	if oldRV.Type() != newRV.Type() {
+		return nil, fmt.Errorf("cannot compare %v with %v: expected values of the same type", oldRV.Type(), newRV.Type())
+	}
+
+	structMeta, err := m.GetStructMetadata(oldRV.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for _, field := range structMeta.Fields {
+		if field.Ignored || field.Transient || !field.Exported || field.Column == "" {
+			continue
+		}
+
+		if !valuesEqual(oldRV, newRV, field) {
+			changed = append(changed, field.Column)
+		}
+	}
+
+	return changed, nil
+}
+
+// valuesEqual reports whether field has deeply equal dereferenced values in a and b.
+func valuesEqual(a, b reflect.Value, field FieldMetadata) bool {
+	aValue, aOK := fieldValue(a, field)
+	bValue, bOK := fieldValue(b, field)
+	if !aOK || !bOK {
+		return aOK == bOK
+	}
+
+	return reflect.DeepEqual(aValue.Interface(), bValue.Interface())
+}
@@ -0,0 +1,111 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadata_ChangedColumns(t *testing.T) {
+	type Audit struct {
+		UpdatedBy string `schema:"updated_by"`
+	}
+	type Product struct {
+		ID      int64             `schema:"id,pk"`
+		Name    string            `schema:"name"`
+		Price   *float64          `schema:"price"`
+		Tags    []string          `schema:"tags"`
+		Attrs   map[string]string `schema:"attrs"`
+		Cache   string            `schema:"-"`
+		Views   int               `schema:"views,transient"`
+		private int
+		*Audit
+	}
+
+	metadata := NewDefaultMetadata()
+	price, samePrice, otherPrice, zero := 9.5, 9.5, 10.0, 0.0
+
+	base := Product{
+		ID:    1,
+		Name:  "Lamp",
+		Price: &price,
+		Tags:  []string{"home"},
+		Attrs: map[string]string{"color": "red"},
+		Audit: &Audit{UpdatedBy: "ada"},
+	}
+
+	tests := []struct {
+		name   string
+		before func(p *Product)
+		mutate func(p *Product)
+		want   []string
+	}{
+		{name: "identical", mutate: func(*Product) {}},
+		{name: "pointers to equal values", mutate: func(p *Product) { p.Price = &samePrice }},
+		{name: "deeply equal collections", mutate: func(p *Product) {
+			p.Tags = []string{"home"}
+			p.Attrs = map[string]string{"color": "red"}
+		}},
+		{name: "ignored, transient and unexported fields", mutate: func(p *Product) {
+			p.Cache = "x"
+			p.Views = 10
+			p.private = 1
+		}},
+		{name: "scalar and pointer values", mutate: func(p *Product) {
+			p.Name = "Desk lamp"
+			p.Price = &otherPrice
+		}, want: []string{"name", "price"}},
+		{name: "both nil pointers", before: func(p *Product) { p.Price = nil }, mutate: func(*Product) {}},
+		{
+			name:   "nil versus pointer to zero",
+			before: func(p *Product) { p.Price = nil },
+			mutate: func(p *Product) { p.Price = &zero },
+			want:   []string{"price"},
+		},
+		{name: "collections", mutate: func(p *Product) {
+			p.Tags = append(p.Tags, "office")
+			p.Attrs = nil
+		}, want: []string{"tags", "attrs"}},
+		{name: "promoted field", mutate: func(p *Product) { p.Audit = &Audit{UpdatedBy: "bob"} }, want: []string{"updated_by"}},
+		{name: "nil embedded pointer", mutate: func(p *Product) { p.Audit = nil }, want: []string{"updated_by"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldVal := base
+			if tt.before != nil {
+				tt.before(&oldVal)
+			}
+			newVal := oldVal
+			tt.mutate(&newVal)
+
+			changed, err := metadata.ChangedColumns(oldVal, &newVal)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, changed)
+		})
+	}
+
+	t.Run("zero values", func(t *testing.T) {
+		changed, err := metadata.ChangedColumns(Product{}, &Product{})
+		require.NoError(t, err)
+		assert.Empty(t, changed)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		type Other struct {
+			Name string `schema:"name"`
+		}
+
+		_, err := metadata.ChangedColumns(Product{}, Other{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot compare schema.Product with schema.Other: expected values of the same type")
+
+		_, err = metadata.ChangedColumns(nil, Product{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot compare nil: expected struct or pointer to struct")
+
+		_, err = metadata.ChangedColumns(Product{}, (*Product)(nil))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot compare nil *schema.Product")
+	})
+}
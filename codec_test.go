@@ -32,6 +32,16 @@ func TestCodec_DecodeRequest(t *testing.T) {
 		Body bodyStruct `body:"structured"`
 	}
 
+	type Pagination struct {
+		Page  int `schema:"page,location=query"`
+		Limit int `schema:"limit,location=query"`
+	}
+
+	type embeddedStruct struct {
+		Pagination
+		Name string `schema:"name,location=query"`
+	}
+
 	tests := []struct {
 		name         string
 		method       string
@@ -67,6 +77,13 @@ func TestCodec_DecodeRequest(t *testing.T) {
 			result:      &mixedStruct{},
 			want:        &mixedStruct{Name: "John", Body: bodyStruct{Title: "Post", Content: "Content"}},
 		},
+		{
+			name:   "embedded struct query parameters",
+			method: "GET",
+			url:    "/test?name=John&page=2&limit=10",
+			result: &embeddedStruct{},
+			want:   &embeddedStruct{Pagination: Pagination{Page: 2, Limit: 10}, Name: "John"},
+		},
 	}
 
 	codec := NewDefaultCodec()
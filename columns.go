@@ -0,0 +1,286 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Column describes a single column derived from a field, for assembling DDL statements.
+type Column struct {
+	// Name is the resolved column name.
+	Name string
+	// SQLType is the SQL type produced by the SQLTypeMapper (e.g. "BIGINT").
+	SQLType string
+	// PrimaryKey indicates the field is tagged as primary key.
+	PrimaryKey bool
+	// NotNull indicates the column cannot hold NULL: the Go type is neither nilable (pointer, slice,
+	// map or interface) nor a sql.Scanner or driver.Valuer such as sql.NullString, or the field is a
+	// primary key or required.
+	NotNull bool
+}
+
+// SQLTypeMapper maps a field to a SQL column type. Implement it to inject dialect-specific
+// types (e.g. Postgres vs. MySQL) via WithSQLTypeMapper.
+type SQLTypeMapper interface {
+	// SQLType returns the SQL type for the field.
+	SQLType(field FieldMetadata) string
+}
+
+// DefaultSQLTypeMapper maps Go types to portable ANSI-style SQL types.
+// Byte slices and arrays ([]byte, [16]byte, ...) are mapped to BLOB; structs, maps and other slices
+// and arrays to JSON. Scalar, sql.Scanner and driver.Valuer
+// types are values rather than documents and are never mapped to JSON: the sql.Null* types (and
+// sql.Null[T]) map by the type of their value, e.g. sql.NullInt64 to BIGINT, other types by their
+// underlying kind (a UUID [16]byte to BLOB), and anything else, such as other structs, to TEXT.
+type DefaultSQLTypeMapper struct{}
+
+// SQLType implements SQLTypeMapper.
+func (DefaultSQLTypeMapper) SQLType(field FieldMetadata) string {
+	typ := derefType(field.Type)
+	if typ == timeType || !(field.IsScalar || field.IsScanner || field.IsValuer) {
+		return sqlTypeOf(typ)
+	}
+
+	if typ.Kind() == reflect.Struct {
+		valueType, ok := nullValueType(typ)
+		if !ok {
+			return "TEXT"
+		}
+		typ = derefType(valueType)
+	}
+	if sqlType := sqlTypeOf(typ); sqlType != "JSON" {
+		return sqlType
+	}
+
+	return "TEXT"
+}
+
+// nullValueType returns the type of the value held by a struct shaped like the sql.Null* types:
+// a value field followed by a Valid bool.
+func nullValueType(typ reflect.Type) (reflect.Type, bool) {
+	if typ.NumField() != 2 || typ.Field(1).Name != "Valid" || typ.Field(1).Type.Kind() != reflect.Bool {
+		return nil, false
+	}
+
+	return typ.Field(0).Type, true
+}
+
+// sqlTypeOf maps the non-pointer type typ to its SQL type.
+func sqlTypeOf(typ reflect.Type) string {
+	//nolint:exhaustive // Remaining kinds are mapped by category below
+	switch typ.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Uint8:
+		return "SMALLINT"
+	case reflect.Int32, reflect.Uint16:
+		return "INTEGER"
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return "BIGINT"
+	case reflect.Float32:
+		return "REAL"
+	case reflect.Float64:
+		return "DOUBLE PRECISION"
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return "BLOB"
+		}
+	}
+
+	//nolint:exhaustive // Numeric kinds are handled above
+	switch categoryOf(typ) {
+	case CategoryString:
+		return "TEXT"
+	case CategoryBool:
+		return "BOOLEAN"
+	case CategoryTime:
+		return "TIMESTAMP"
+	case CategoryStruct, CategorySlice, CategoryMap:
+		return "JSON"
+	default:
+		return "TEXT"
+	}
+}
+
+// Columns returns a column descriptor for every persisted field in declaration order.
+// Ignored, transient and unexported fields are skipped. SQL types come from the SQLTypeMapper set
+// with WithSQLTypeMapper, or DefaultSQLTypeMapper.
+func (m *StructMetadata) Columns() []Column {
+	mapper := m.sqlTypeMapper
+	if mapper == nil {
+		mapper = DefaultSQLTypeMapper{}
+	}
+
+	columns := make([]Column, 0, len(m.Fields))
+	for _, field := range m.Fields {
+		if field.Ignored || field.Transient || !field.Exported || field.Column == "" {
+			continue
+		}
+
+		columns = append(columns, Column{
+			Name:       field.Column,
+			SQLType:    mapper.SQLType(field),
+			PrimaryKey: field.IsPrimaryKey,
+			NotNull:    !(isNilableKind(field.Type.Kind()) || field.IsScanner || field.IsValuer) || field.IsPrimaryKey || field.Required,
+		})
+	}
+
+	return columns
+}
+
+// FieldSetOption configures the field set returned by UpdatableFields.
+type FieldSetOption func(cfg *fieldSetConfig)
+
+type fieldSetConfig struct {
+	primaryKeys bool
+}
+
+// WithPrimaryKeys keeps primary key fields in UpdatableFields, which excludes them by default.
+func WithPrimaryKeys() FieldSetOption {
+	return func(cfg *fieldSetConfig) {
+		cfg.primaryKeys = true
+	}
+}
+
+// InsertableFields returns the persisted fields whose values belong in an INSERT, in declaration order.
+// Read-only fields are excluded, as are the fields skipped by Columns (ignored, transient, unexported, no column).
+// Primary keys are included; mark generated keys read-only to leave them out.
+func (m *StructMetadata) InsertableFields() []FieldMetadata {
+	return m.writableFields(&fieldSetConfig{primaryKeys: true})
+}
+
+// UpdatableFields returns the persisted fields whose values belong in an UPDATE ... SET, in declaration order.
+// Like InsertableFields it excludes read-only fields; primary keys are excluded too unless WithPrimaryKeys is given.
+func (m *StructMetadata) UpdatableFields(opts ...FieldSetOption) []FieldMetadata {
+	cfg := &fieldSetConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return m.writableFields(cfg)
+}
+
+// ColumnNames returns the resolved column names of the insertable fields (see InsertableFields) of v,
+// in declaration order, using the cached metadata. v is a struct, a pointer to struct (a typed nil
+// pointer is fine) or a reflect.Type of either. The names are those of ToMap's keys, without read-only
+// fields, so they line up with the values of an INSERT built from ToMap.
+func (m *Metadata) ColumnNames(v any) ([]string, error) {
+	var structMeta *StructMetadata
+	var err error
+	if typ, ok := v.(reflect.Type); ok {
+		structMeta, err = m.ParseType(typ)
+	} else {
+		structMeta, err = m.Parse(v)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fields := structMeta.InsertableFields()
+	names := make([]string, len(fields))
+	for i := range fields {
+		names[i] = fields[i].Column
+	}
+
+	return names, nil
+}
+
+// ExtractRows returns the values of columns for each element of slice, one row per element in order,
+// as the arguments of a bulk INSERT. slice is a slice or array of structs or pointers to structs, which
+// are dereferenced; with an interface element type, such as []any, every element must have the
+// struct type of the first one. The columns are resolved once with FieldByColumn, so unknown columns,
+// and those of ignored, transient or unexported fields, fail before any element is read. Values are read as
+// ToMap does: pointer fields are dereferenced, and nil pointers, or fields behind a nil embedded
+// pointer, yield nil.
+func (m *Metadata) ExtractRows(slice any, columns []string) ([][]any, error) {
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("cannot extract rows from %v: expected slice of structs", describeElem(rv))
+	}
+
+	rows := make([][]any, rv.Len())
+	if rv.Len() == 0 {
+		return rows, nil
+	}
+
+	structType := derefType(rv.Type().Elem())
+	if structType.Kind() == reflect.Interface {
+		first, ok := structRoot(rv.Index(0).Elem())
+		if !ok {
+			return nil, fmt.Errorf("cannot extract rows: element 0 is %v, expected struct or pointer to struct", describeElem(rv.Index(0).Elem()))
+		}
+		structType = first.Type()
+	}
+
+	structMeta, err := m.GetStructMetadata(structType)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]FieldMetadata, len(columns))
+	for i, column := range columns {
+		field, ok := structMeta.FieldByColumn(column)
+		if !ok || field.Ignored || field.Transient || !field.Exported {
+			return nil, fmt.Errorf("cannot extract rows: unknown column %q for %v", column, structType)
+		}
+		fields[i] = field
+	}
+
+	for i := range rows {
+		elem := rv.Index(i)
+		if elem.Kind() == reflect.Interface {
+			elem = elem.Elem()
+		}
+		root, ok := structRoot(elem)
+		if !ok || root.Type() != structType {
+			return nil, fmt.Errorf("cannot extract rows: element %d is %v, expected %v", i, describeElem(elem), structType)
+		}
+
+		row := make([]any, len(fields))
+		for j := range fields {
+			if value, ok := fieldValue(root, fields[j]); ok {
+				row[j] = value.Interface()
+			}
+		}
+		rows[i] = row
+	}
+
+	return rows, nil
+}
+
+// describeElem describes a slice element for error messages, distinguishing nil values.
+func describeElem(elem reflect.Value) string {
+	if !elem.IsValid() {
+		return "nil"
+	}
+	if elem.Kind() == reflect.Pointer && elem.IsNil() {
+		return "nil " + elem.Type().String()
+	}
+
+	return valueType(elem)
+}
+
+// writableFields returns the persisted, non read-only fields, filtered by cfg.
+func (m *StructMetadata) writableFields(cfg *fieldSetConfig) []FieldMetadata {
+	fields := make([]FieldMetadata, 0, len(m.Fields))
+	for _, field := range m.Fields {
+		if field.Ignored || field.Transient || !field.Exported || field.Column == "" || field.ReadOnly {
+			continue
+		}
+		if field.IsPrimaryKey && !cfg.primaryKeys {
+			continue
+		}
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// isNilableKind reports whether values of the kind can be nil.
+func isNilableKind(kind reflect.Kind) bool {
+	//nolint:exhaustive // Only nilable kinds are listed
+	switch kind {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	default:
+		return false
+	}
+}
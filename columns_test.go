@@ -0,0 +1,336 @@
+package schema
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// postgresTypes is a dialect-specific mapper for testing.
+type postgresTypes struct {
+	DefaultSQLTypeMapper
+}
+
+func (p postgresTypes) SQLType(field FieldMetadata) string {
+	if field.Category() == CategoryTime {
+		return "TIMESTAMPTZ"
+	}
+	if field.IsSlice && field.ElemType.Kind() == reflect.Uint8 {
+		return "BYTEA"
+	}
+
+	return p.DefaultSQLTypeMapper.SQLType(field)
+}
+
+// columnsUUID and columnsLabels are driver.Valuer types with non-struct underlying types.
+type columnsUUID [16]byte
+
+func (u columnsUUID) Value() (driver.Value, error) { return u[:], nil }
+
+type columnsLabels []string
+
+func (l columnsLabels) Value() (driver.Value, error) { return strings.Join(l, ","), nil }
+
+type columnsAccount struct {
+	ID        int64             `schema:"id,pk"`
+	Email     string            `schema:"email"`
+	Nickname  *string           `schema:"nickname"`
+	Plan      *string           `schema:"plan,required"`
+	Active    bool              `schema:"active"`
+	Score     float32           `schema:"score"`
+	Balance   float64           `schema:"balance"`
+	Age       uint8             `schema:"age"`
+	Logins    int32             `schema:"logins"`
+	Avatar    []byte            `schema:"avatar"`
+	Digest    [16]byte          `schema:"digest"`
+	Hash      [32]byte          `schema:"hash"`
+	Tags      []string          `schema:"tags"`
+	Settings  map[string]string `schema:"settings"`
+	CreatedAt time.Time         `schema:"created_at"`
+	Internal  string            `schema:"-"`
+}
+
+func TestStructMetadata_Columns(t *testing.T) {
+	t.Run("default mapper", func(t *testing.T) {
+		structMeta, err := NewDefaultMetadata().GetStructMetadata(reflect.TypeOf(columnsAccount{}))
+		require.NoError(t, err)
+
+		assert.Equal(t, []Column{
+			{Name: "id", SQLType: "BIGINT", PrimaryKey: true, NotNull: true},
+			{Name: "email", SQLType: "TEXT", NotNull: true},
+			{Name: "nickname", SQLType: "TEXT"},
+			{Name: "plan", SQLType: "TEXT", NotNull: true},
+			{Name: "active", SQLType: "BOOLEAN", NotNull: true},
+			{Name: "score", SQLType: "REAL", NotNull: true},
+			{Name: "balance", SQLType: "DOUBLE PRECISION", NotNull: true},
+			{Name: "age", SQLType: "SMALLINT", NotNull: true},
+			{Name: "logins", SQLType: "INTEGER", NotNull: true},
+			{Name: "avatar", SQLType: "BLOB"},
+			{Name: "digest", SQLType: "BLOB", NotNull: true},
+			{Name: "hash", SQLType: "BLOB", NotNull: true},
+			{Name: "tags", SQLType: "JSON"},
+			{Name: "settings", SQLType: "JSON"},
+			{Name: "created_at", SQLType: "TIMESTAMP", NotNull: true},
+		}, structMeta.Columns())
+	})
+
+	t.Run("custom mapper", func(t *testing.T) {
+		metadata := NewDefaultMetadata(WithSQLTypeMapper(postgresTypes{}))
+		structMeta, err := metadata.GetStructMetadata(reflect.TypeOf(columnsAccount{}))
+		require.NoError(t, err)
+
+		types := make(map[string]string)
+		for _, column := range structMeta.Columns() {
+			types[column.Name] = column.SQLType
+		}
+		assert.Equal(t, "TIMESTAMPTZ", types["created_at"])
+		assert.Equal(t, "BYTEA", types["avatar"])
+		assert.Equal(t, "BIGINT", types["id"])
+	})
+
+	t.Run("sql null types", func(t *testing.T) {
+		type Contact struct {
+			ID       int64             `schema:"id,pk"`
+			Phone    sql.NullString    `schema:"phone"`
+			Visits   sql.NullInt64     `schema:"visits"`
+			SeenAt   sql.NullTime      `schema:"seen_at"`
+			Verified sql.Null[bool]    `schema:"verified"`
+			Email    sql.NullString    `schema:"email,required"`
+			Extra    map[string]string `schema:"extra"`
+		}
+		structMeta, err := NewDefaultMetadata().Parse(Contact{})
+		require.NoError(t, err)
+
+		assert.Equal(t, []Column{
+			{Name: "id", SQLType: "BIGINT", PrimaryKey: true, NotNull: true},
+			{Name: "phone", SQLType: "TEXT"},
+			{Name: "visits", SQLType: "BIGINT"},
+			{Name: "seen_at", SQLType: "TIMESTAMP"},
+			{Name: "verified", SQLType: "BOOLEAN"},
+			{Name: "email", SQLType: "TEXT", NotNull: true},
+			{Name: "extra", SQLType: "JSON"},
+		}, structMeta.Columns())
+	})
+
+	t.Run("registered scalar types", func(t *testing.T) {
+		type money struct {
+			Units int64
+			Nanos int32
+		}
+		type Invoice struct {
+			Total money `schema:"total"`
+		}
+		structMeta, err := NewDefaultMetadata(WithScalarTypes(reflect.TypeOf(money{}))).Parse(Invoice{})
+		require.NoError(t, err)
+
+		assert.Equal(t, []Column{{Name: "total", SQLType: "TEXT", NotNull: true}}, structMeta.Columns())
+	})
+
+	t.Run("valuers with non-struct underlying types", func(t *testing.T) {
+		type Device struct {
+			ID     columnsUUID   `schema:"id,pk"`
+			Labels columnsLabels `schema:"labels"`
+		}
+		structMeta, err := NewDefaultMetadata().Parse(Device{})
+		require.NoError(t, err)
+
+		assert.Equal(t, []Column{
+			{Name: "id", SQLType: "BLOB", PrimaryKey: true, NotNull: true},
+			{Name: "labels", SQLType: "TEXT"},
+		}, structMeta.Columns())
+	})
+}
+
+func TestStructMetadata_InsertableAndUpdatableFields(t *testing.T) {
+	type Order struct {
+		ID        int64     `schema:"id,pk,readonly"`
+		TenantID  int64     `schema:"tenant_id,pk"`
+		Total     float64   `schema:"total"`
+		Status    string    `schema:"status"`
+		Reference string    `schema:"reference,->"`
+		CreatedAt time.Time `schema:"created_at,readonly"`
+		Notes     string    `schema:"-"`
+	}
+
+	structMeta, err := NewDefaultMetadata().GetStructMetadata(reflect.TypeOf(Order{}))
+	require.NoError(t, err)
+
+	names := func(fields []FieldMetadata) []string {
+		result := make([]string, 0, len(fields))
+		for _, field := range fields {
+			result = append(result, field.StructFieldName)
+		}
+
+		return result
+	}
+
+	assert.True(t, structMeta.MustFieldByName("Reference").ReadOnly)
+	assert.False(t, structMeta.MustFieldByName("Status").ReadOnly)
+
+	assert.Equal(t, []string{"TenantID", "Total", "Status"}, names(structMeta.InsertableFields()))
+	assert.Equal(t, []string{"Total", "Status"}, names(structMeta.UpdatableFields()))
+	assert.Equal(t, []string{"TenantID", "Total", "Status"}, names(structMeta.UpdatableFields(WithPrimaryKeys())))
+}
+
+func TestMetadata_ColumnNames(t *testing.T) {
+	type Audit struct {
+		CreatedBy string `schema:"created_by"`
+	}
+	type Order struct {
+		ID     int64 `schema:"id,pk,readonly"`
+		Total  float64
+		Status string `schema:"status"`
+		Notes  string `schema:"-"`
+		Audit
+	}
+
+	metadata := NewDefaultMetadata()
+	want := []string{"total", "status", "created_by"}
+
+	for _, v := range []any{Order{}, &Order{}, (*Order)(nil), reflect.TypeOf(Order{}), reflect.TypeOf(&Order{})} {
+		names, err := metadata.ColumnNames(v)
+		require.NoError(t, err)
+		assert.Equal(t, want, names)
+	}
+
+	names, err := metadata.ColumnNames(struct{}{})
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	_, err = metadata.ColumnNames(nil)
+	require.Error(t, err)
+	_, err = metadata.ColumnNames(42)
+	require.Error(t, err)
+	_, err = metadata.ColumnNames(reflect.TypeOf(""))
+	require.Error(t, err)
+}
+
+func TestMetadata_ExtractRows(t *testing.T) {
+	type Audit struct {
+		CreatedBy string `schema:"created_by"`
+	}
+	type Order struct {
+		ID     int64   `schema:"id,pk"`
+		Total  float64 `schema:"total"`
+		Note   *string `schema:"note"`
+		Secret string  `schema:"-"`
+		Ref    string  `schema:"ref,alias=reference"`
+		*Audit `schema:",inline"`
+	}
+
+	metadata := NewDefaultMetadata()
+	note := "gift"
+	columns := []string{"total", "id", "note", "created_by", "reference"}
+	want := [][]any{
+		{9.5, int64(1), "gift", "ada", "A-1"},
+		{0.0, int64(2), nil, nil, ""},
+	}
+	first := Order{ID: 1, Total: 9.5, Note: &note, Ref: "A-1", Audit: &Audit{CreatedBy: "ada"}}
+	second := Order{ID: 2}
+
+	for name, slice := range map[string]any{
+		"values":     []Order{first, second},
+		"pointers":   []*Order{&first, &second},
+		"interfaces": []any{first, &second},
+		"array":      [2]Order{first, second},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rows, err := metadata.ExtractRows(slice, columns)
+			require.NoError(t, err)
+			assert.Equal(t, want, rows)
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		rows, err := metadata.ExtractRows([]Order{}, columns)
+		require.NoError(t, err)
+		assert.Empty(t, rows)
+
+		rows, err = metadata.ExtractRows([]Order{first}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, [][]any{{}}, rows)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		type Other struct {
+			ID int64 `schema:"id"`
+		}
+
+		tests := []struct {
+			name    string
+			slice   any
+			columns []string
+			wantErr string
+		}{
+			{name: "not a slice", slice: first, columns: columns, wantErr: "cannot extract rows from schema.Order: expected slice of structs"},
+			{name: "nil", slice: nil, columns: columns, wantErr: "cannot extract rows from nil: expected slice of structs"},
+			{name: "unknown column", slice: []Order{first}, columns: []string{"id", "missing"}, wantErr: `unknown column "missing" for schema.Order`},
+			{name: "ignored column", slice: []Order{first}, columns: []string{"secret"}, wantErr: `unknown column "secret"`},
+			{name: "mixed types", slice: []any{first, Other{}}, columns: columns, wantErr: "element 1 is schema.Other, expected schema.Order"},
+			{name: "nil pointer", slice: []*Order{&first, nil}, columns: columns, wantErr: "element 1 is nil *schema.Order, expected schema.Order"},
+			{name: "nil interface", slice: []any{nil}, columns: columns, wantErr: "element 0 is nil, expected struct or pointer to struct"},
+			{name: "not structs", slice: []int{1}, columns: columns, wantErr: "expected struct type"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := metadata.ExtractRows(tt.slice, tt.columns)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+}
+
+func TestStructMetadata_Columns_Transient(t *testing.T) {
+	type Document struct {
+		ID      int64  `schema:"id,pk"`
+		Title   string `schema:"title"`
+		Preview string `schema:"preview,transient"`
+		Rank    int    `schema:"rank,readonly"`
+		Cache   string `schema:"-"`
+	}
+
+	metadata := NewDefaultMetadata()
+	structMeta, err := metadata.Parse(Document{})
+	require.NoError(t, err)
+
+	columnNames := func(columns []Column) []string {
+		names := make([]string, 0, len(columns))
+		for _, column := range columns {
+			names = append(names, column.Name)
+		}
+
+		return names
+	}
+	fieldColumns := func(fields []FieldMetadata) []string {
+		names := make([]string, 0, len(fields))
+		for _, field := range fields {
+			names = append(names, field.Column)
+		}
+
+		return names
+	}
+
+	assert.Equal(t, []string{"id", "title", "rank"}, columnNames(structMeta.Columns()))
+	assert.Equal(t, []string{"id", "title"}, fieldColumns(structMeta.InsertableFields()))
+	assert.Equal(t, []string{"title"}, fieldColumns(structMeta.UpdatableFields()))
+
+	names, err := metadata.ColumnNames(Document{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "title"}, names)
+
+	_, err = metadata.ExtractRows([]Document{{}}, []string{"id", "preview"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown column "preview"`)
+
+	preview, ok := structMeta.FieldByColumn("preview")
+	require.True(t, ok, "lookups still find transient fields")
+	assert.True(t, preview.Transient)
+	assert.Contains(t, preview.String(), "flags=transient")
+}
@@ -0,0 +1,109 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// ApplyDefaults sets every zero-valued field that has a "default=..." tag option to its default,
+// converted to the field's type. Zero values are detected with reflect.Value.IsZero.
+// v must be a non-nil pointer to a struct.
+func (m *Metadata) ApplyDefaults(v any) error {
+	rv, err := resolveStructRoot(v, "apply defaults to %s", true)
+	if err != nil {
+		return err
+	}
+
+	structMeta, err := m.GetStructMetadata(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, field := range structMeta.Fields {
+		if field.Default == "" || field.Ignored || !field.Exported {
+			continue
+		}
+
+		// A nil embedded pointer on the path means the field is unset
+		if current, err := rv.FieldByIndexErr(field.IndexPath); err == nil && !current.IsZero() {
+			continue
+		}
+
+		value, err := parseStringValue(field.Default, field.Type)
+		if err != nil {
+			return fmt.Errorf("field %s: invalid default %q: %w", field.StructFieldName, field.Default, err)
+		}
+
+		target, err := fieldByIndexAlloc(rv, field.IndexPath)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.StructFieldName, err)
+		}
+		target.Set(value)
+	}
+
+	return nil
+}
+
+// parseStringValue converts s to a value of typ, allocating pointers as needed.
+// Supported types are strings, bools, integers, unsigned integers, floats and time.Duration.
+func parseStringValue(s string, typ reflect.Type) (reflect.Value, error) {
+	if typ.Kind() == reflect.Pointer {
+		elem, err := parseStringValue(s, typ.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(elem)
+
+		return ptr, nil
+	}
+
+	value := reflect.New(typ).Elem()
+	if typ == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value.SetInt(int64(d))
+
+		return value, nil
+	}
+
+	//nolint:exhaustive // Remaining kinds are unsupported
+	switch typ.Kind() {
+	case reflect.String:
+		value.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, typ.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, typ.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, typ.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value.SetFloat(f)
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type %v", typ)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadata_ApplyDefaults(t *testing.T) {
+	type Config struct {
+		Host    string        `schema:"host,default=localhost"`
+		Port    int           `schema:"port,default=8080"`
+		Debug   bool          `schema:"debug,default=true"`
+		Ratio   float64       `schema:"ratio,default=0.5"`
+		Retries *uint8        `schema:"retries,default=3"`
+		Timeout time.Duration `schema:"timeout,default=5s"`
+		Name    string        `schema:"name"`
+	}
+
+	metadata := NewDefaultMetadata()
+
+	t.Run("fills zero values", func(t *testing.T) {
+		var cfg Config
+		require.NoError(t, metadata.ApplyDefaults(&cfg))
+
+		assert.Equal(t, "localhost", cfg.Host)
+		assert.Equal(t, 8080, cfg.Port)
+		assert.True(t, cfg.Debug)
+		assert.InDelta(t, 0.5, cfg.Ratio, 0)
+		require.NotNil(t, cfg.Retries)
+		assert.Equal(t, uint8(3), *cfg.Retries)
+		assert.Equal(t, 5*time.Second, cfg.Timeout)
+		assert.Empty(t, cfg.Name)
+	})
+
+	t.Run("keeps non-zero values", func(t *testing.T) {
+		retries := uint8(0)
+		cfg := Config{Host: "example.com", Port: 9090, Retries: &retries}
+		require.NoError(t, metadata.ApplyDefaults(&cfg))
+
+		assert.Equal(t, "example.com", cfg.Host)
+		assert.Equal(t, 9090, cfg.Port)
+		assert.Same(t, &retries, cfg.Retries, "non-nil pointer is not zero")
+		assert.Equal(t, uint8(0), *cfg.Retries)
+	})
+
+	t.Run("unconvertible default", func(t *testing.T) {
+		type Bad struct {
+			Port int8 `schema:"port,default=300"`
+		}
+
+		err := metadata.ApplyDefaults(&Bad{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `field Port: invalid default "300"`)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		type Bad struct {
+			Tags []string `schema:"tags,default=a"`
+		}
+
+		err := metadata.ApplyDefaults(&Bad{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported type []string")
+	})
+
+	t.Run("invalid destination", func(t *testing.T) {
+		err := metadata.ApplyDefaults(Config{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot apply defaults to schema.Config")
+	})
+}
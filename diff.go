@@ -0,0 +1,71 @@
+package schema
+
+// SchemaDiff describes how the columns of one struct version differ from another, as returned by Diff.
+type SchemaDiff struct {
+	// Added are the columns present only in the new metadata, in its declaration order.
+	Added []Column
+	// Removed are the columns present only in the old metadata, in its declaration order.
+	Removed []Column
+	// Changed are the columns present in both whose SQL type, primary key or nullability differ,
+	// in the new metadata's declaration order.
+	Changed []ColumnChange
+}
+
+// ColumnChange is a column whose descriptor differs between two struct versions.
+type ColumnChange struct {
+	// Name is the resolved column name shared by both versions.
+	Name string
+	// Old and New are the column descriptors before and after the change.
+	Old Column
+	New Column
+}
+
+// Empty reports whether the diff contains no changes.
+func (d SchemaDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff compares the persisted columns (see StructMetadata.Columns) of an old and a new version of a struct,
+// matching them by resolved column name. Types are compared by their mapped SQL type rather than by
+// reflect.Type, so int64 replaced by a named int64 type is not a change, while int32 to int64 is.
+// A nil argument is treated as a struct without columns.
+func Diff(oldMeta, newMeta *StructMetadata) SchemaDiff {
+	oldColumns := columnsOf(oldMeta)
+	newColumns := columnsOf(newMeta)
+
+	oldByName := make(map[string]Column, len(oldColumns))
+	for _, column := range oldColumns {
+		oldByName[column.Name] = column
+	}
+	newByName := make(map[string]bool, len(newColumns))
+
+	var diff SchemaDiff
+	for _, column := range newColumns {
+		newByName[column.Name] = true
+
+		previous, exists := oldByName[column.Name]
+		switch {
+		case !exists:
+			diff.Added = append(diff.Added, column)
+		case previous != column:
+			diff.Changed = append(diff.Changed, ColumnChange{Name: column.Name, Old: previous, New: column})
+		}
+	}
+
+	for _, column := range oldColumns {
+		if !newByName[column.Name] {
+			diff.Removed = append(diff.Removed, column)
+		}
+	}
+
+	return diff
+}
+
+// columnsOf returns the columns of m, or nil if m is nil.
+func columnsOf(m *StructMetadata) []Column {
+	if m == nil {
+		return nil
+	}
+
+	return m.Columns()
+}
@@ -0,0 +1,68 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type diffUserID int64
+
+type diffUserV1 struct {
+	ID       int64   `schema:"id,pk"`
+	Email    string  `schema:"email"`
+	Nickname *string `schema:"nickname"`
+	Logins   int32   `schema:"logins"`
+	Legacy   string  `schema:"legacy"`
+}
+
+type diffUserV2 struct {
+	ID       diffUserID `schema:"id,pk"`
+	Email    string     `schema:"email"`
+	Nickname string     `schema:"nickname"`
+	Logins   int64      `schema:"logins"`
+	Plan     string     `schema:"plan"`
+}
+
+func TestDiff(t *testing.T) {
+	metadata := NewDefaultMetadata()
+	v1, err := metadata.Parse(diffUserV1{})
+	require.NoError(t, err)
+	v2, err := metadata.Parse(diffUserV2{})
+	require.NoError(t, err)
+
+	t.Run("added, removed and changed columns", func(t *testing.T) {
+		diff := Diff(v1, v2)
+
+		assert.False(t, diff.Empty())
+		assert.Equal(t, []Column{{Name: "plan", SQLType: "TEXT", NotNull: true}}, diff.Added)
+		assert.Equal(t, []Column{{Name: "legacy", SQLType: "TEXT", NotNull: true}}, diff.Removed)
+		assert.Equal(t, []ColumnChange{
+			{
+				Name: "nickname",
+				Old:  Column{Name: "nickname", SQLType: "TEXT"},
+				New:  Column{Name: "nickname", SQLType: "TEXT", NotNull: true},
+			},
+			{
+				Name: "logins",
+				Old:  Column{Name: "logins", SQLType: "INTEGER", NotNull: true},
+				New:  Column{Name: "logins", SQLType: "BIGINT", NotNull: true},
+			},
+		}, diff.Changed, "a named type with the same SQL type is not a change")
+	})
+
+	t.Run("identical", func(t *testing.T) {
+		assert.True(t, Diff(v1, v1).Empty())
+	})
+
+	t.Run("nil metadata", func(t *testing.T) {
+		diff := Diff(nil, v1)
+		assert.Len(t, diff.Added, 5)
+		assert.Empty(t, diff.Removed)
+
+		diff = Diff(v1, nil)
+		assert.Empty(t, diff.Added)
+		assert.Len(t, diff.Removed, 5)
+	})
+}
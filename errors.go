@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ValidationRule identifies the metadata rule a field failed.
+type ValidationRule string
+
+const (
+	// ErrEmptyName is reported when StructFieldName is empty.
+	ErrEmptyName ValidationRule = "empty_name"
+	// ErrNilType is reported when Type is nil.
+	ErrNilType ValidationRule = "nil_type"
+	// ErrNegativeIndex is reported when Index is negative.
+	ErrNegativeIndex ValidationRule = "negative_index"
+	// ErrDuplicateColumn is reported when two fields resolve to the same column name.
+	ErrDuplicateColumn ValidationRule = "duplicate_column"
+	// ErrDuplicateTagName is reported when two fields declare the same explicit tag name.
+	ErrDuplicateTagName ValidationRule = "duplicate_tag_name"
+	// ErrInvalidColumn is reported when the validator set with WithColumnValidator rejects a column name.
+	ErrInvalidColumn ValidationRule = "invalid_column"
+	// ErrDuplicateIndexPath is reported when two fields claim the same IndexPath.
+	ErrDuplicateIndexPath ValidationRule = "duplicate_index_path"
+	// ErrInvalidIndexPath is reported when an IndexPath does not resolve on the struct type, or when
+	// Index or IsNested disagree with it.
+	ErrInvalidIndexPath ValidationRule = "invalid_index_path"
+	// ErrUnknownOption is reported in strict tag mode when a tag contains an unregistered option.
+	ErrUnknownOption ValidationRule = "unknown_option"
+	// ErrMissingTag is reported with WithRequireTag when an exported field has no tag under the tag key.
+	ErrMissingTag ValidationRule = "missing_tag"
+	// ErrFieldConflict is reported when a field promoted by the inline tag option has the same name
+	// as a field declared directly on the struct it is inlined into.
+	ErrFieldConflict ValidationRule = "field_conflict"
+	// ErrRequired is reported by CheckRequired when a required field is not set.
+	ErrRequired ValidationRule = "required"
+)
+
+// ErrImmutableMetadata is returned by mutating methods, such as StructMetadata.Reindex, called on metadata
+// parsed with WithImmutable. Clone the metadata to get a mutable copy.
+var ErrImmutableMetadata = errors.New("schema: metadata is immutable, Clone it to mutate")
+
+// FieldError describes a metadata rule failure for a single field.
+// Use errors.As to inspect it, including through errors returned by Validate.
+type FieldError struct {
+	// Field is the struct field name (may be empty when the name itself is invalid).
+	Field string
+	// Rule is the rule that failed.
+	Rule ValidationRule
+	// Message is the human-readable description of the failure.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+
+	return fmt.Sprintf("field %q: %s", e.Field, e.Message)
+}
+
+// newFieldError creates a FieldError with a formatted message.
+func newFieldError(field string, rule ValidationRule, format string, args ...any) *FieldError {
+	return &FieldError{
+		Field:   field,
+		Rule:    rule,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
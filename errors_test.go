@@ -0,0 +1,78 @@
+package schema
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldError_Error(t *testing.T) {
+	t.Run("with field name", func(t *testing.T) {
+		err := newFieldError("Name", ErrNilType, "type cannot be nil")
+		assert.Equal(t, `field "Name": type cannot be nil`, err.Error())
+	})
+
+	t.Run("without field name", func(t *testing.T) {
+		err := newFieldError("", ErrEmptyName, "structFieldName cannot be empty")
+		assert.Equal(t, "structFieldName cannot be empty", err.Error())
+	})
+}
+
+// fieldErrorRules collects the rules of all FieldErrors in a (possibly joined) error tree.
+func fieldErrorRules(err error) []ValidationRule {
+	var rules []ValidationRule
+	if joined, ok := err.(interface{ Unwrap() []error }); ok { //nolint:errorlint // Walking a joined error tree
+		for _, e := range joined.Unwrap() {
+			rules = append(rules, fieldErrorRules(e)...)
+		}
+
+		return rules
+	}
+
+	if fieldErr, ok := err.(*FieldError); ok { //nolint:errorlint // Leaf of the error tree
+		return append(rules, fieldErr.Rule)
+	}
+
+	if wrapped := errors.Unwrap(err); wrapped != nil {
+		return fieldErrorRules(wrapped)
+	}
+
+	return rules
+}
+
+func TestFieldError_ErrorsAs(t *testing.T) {
+	t.Run("validateField", func(t *testing.T) {
+		err := validateField(FieldMetadata{StructFieldName: "Age", Type: reflect.TypeOf(0), Index: -1})
+
+		var fieldErr *FieldError
+		require.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "Age", fieldErr.Field)
+		assert.Equal(t, ErrNegativeIndex, fieldErr.Rule)
+		assert.Equal(t, "index must be non-negative, got -1", fieldErr.Message)
+	})
+
+	t.Run("NewStructMetadata", func(t *testing.T) {
+		_, err := NewStructMetadata(nil, []FieldMetadata{{StructFieldName: "Name"}})
+
+		var fieldErr *FieldError
+		require.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, ErrNilType, fieldErr.Rule)
+	})
+
+	t.Run("Validate reports every rule", func(t *testing.T) {
+		structMeta := &StructMetadata{
+			Fields: []FieldMetadata{
+				{StructFieldName: "", Type: reflect.TypeOf(""), Index: 0},
+				{StructFieldName: "ID", Type: reflect.TypeOf(0), Index: 1, Column: "id", Tag: FieldTag{Name: "id"}},
+				{StructFieldName: "AltID", Type: reflect.TypeOf(0), Index: 2, Column: "id", Tag: FieldTag{Name: "id"}},
+				{StructFieldName: "Code", Type: reflect.TypeOf(0), Index: 3, Column: "id"},
+			},
+		}
+
+		rules := fieldErrorRules(structMeta.Validate())
+		assert.ElementsMatch(t, []ValidationRule{ErrEmptyName, ErrDuplicateTagName, ErrDuplicateColumn}, rules)
+	})
+}
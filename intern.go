@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"strings"
+	"sync"
+)
+
+// snakeCaseColumns memoizes SnakeCaseNaming columns by field name for the builder (see columnName).
+// Field names come from struct types, so the table is bounded by the program's types.
+var snakeCaseColumns sync.Map // map[string]string
+
+// internedStrings holds the canonical copies returned by intern. Entries are never dropped: like
+// snakeCaseColumns, the table is bounded by the column and prefix names of the program's types.
+var internedStrings sync.Map // map[string]string
+
+// intern returns s backed by a canonical copy shared by all identical strings, so the columns of many
+// similar structs (id, created_at, ...) do not each hold their own. It is safe for concurrent use.
+func intern(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	if cached, ok := internedStrings.Load(s); ok {
+		if canonical, ok := cached.(string); ok {
+			return canonical
+		}
+	}
+
+	cached, _ := internedStrings.LoadOrStore(s, strings.Clone(s))
+	if canonical, ok := cached.(string); ok {
+		return canonical
+	}
+
+	return s
+}
+
+// columnName returns the interned column the naming strategy derives from fieldName. Results of the
+// default SnakeCaseNaming are memoized, since registering many types converts the same names repeatedly.
+func (b *metadataBuilder) columnName(fieldName string) string {
+	if _, ok := b.naming.(SnakeCaseNaming); !ok {
+		return intern(b.naming.Column(fieldName))
+	}
+
+	if cached, ok := snakeCaseColumns.Load(fieldName); ok {
+		if column, ok := cached.(string); ok {
+			return column
+		}
+	}
+
+	column := intern(b.naming.Column(fieldName))
+	snakeCaseColumns.Store(fieldName, column)
+
+	return column
+}
@@ -0,0 +1,386 @@
+package schema
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ToMapOption configures a single ToMap call.
+type ToMapOption func(cfg *toMapConfig)
+
+type toMapConfig struct {
+	includeZeroValues bool
+}
+
+// WithIncludeZeroValues makes ToMap include every non-ignored field regardless of the "omitempty"
+// tag option, e.g. to write every column in an UPDATE: nil pointers map to nil and zero values
+// are kept as they are. It affects only the call it is passed to.
+func WithIncludeZeroValues() ToMapOption {
+	return func(cfg *toMapConfig) {
+		cfg.includeZeroValues = true
+	}
+}
+
+// FromMapOption configures a single FromMap call.
+type FromMapOption func(cfg *fromMapConfig)
+
+type fromMapConfig struct {
+	strictKeys bool
+}
+
+// typeConverter is a per-type value conversion registered with WithTypeDecoder or WithTypeEncoder.
+type typeConverter = func(any) (any, error)
+
+// WithStrictKeys makes FromMap fail when the map contains keys that match no column.
+// By default unknown keys are ignored.
+func WithStrictKeys() FromMapOption {
+	return func(cfg *fromMapConfig) {
+		cfg.strictKeys = true
+	}
+}
+
+// ToMap converts a struct (or pointer to struct) into a map of resolved column name to field value,
+// using the cached struct metadata. Ignored, transient and unexported fields are skipped.
+// Pointer fields are dereferenced; nil pointers produce a nil entry. Fields with the "omitempty" tag
+// option are left out when their value is empty according to IsEmptyValue (or a nil embedded pointer
+// is on their path), unless WithIncludeZeroValues is passed. Values whose type has an encoder
+// registered with WithTypeEncoder are stored as the encoder's result. Otherwise fields
+// implementing encoding.TextMarshaler (e.g. net.IP) are stored as text, except scalar fields such as
+// time.Time, which keep their value for the database driver.
+func (m *Metadata) ToMap(v any, opts ...ToMapOption) (map[string]any, error) {
+	cfg := &toMapConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	rv, err := resolveStructRoot(v, "convert %s to map", false)
+	if err != nil {
+		return nil, err
+	}
+
+	structMeta, err := m.GetStructMetadata(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]any, len(structMeta.Fields))
+	for _, field := range structMeta.Fields {
+		if field.Ignored || field.Transient || !field.Exported {
+			continue
+		}
+
+		if field.OmitEmpty && !cfg.includeZeroValues {
+			if raw, ok := field.GetValueOrZero(rv); !ok || IsEmptyValue(raw) {
+				continue
+			}
+		}
+
+		value, ok := fieldValue(rv, field)
+		if !ok {
+			result[field.Column] = nil
+
+			continue
+		}
+
+		if encode, ok := m.cache.builder.typeEncoders[value.Type()]; ok {
+			encoded, err := encode(value.Interface())
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.StructFieldName, err)
+			}
+			result[field.Column] = encoded
+
+			continue
+		}
+
+		if field.IsTextMarshaler && !field.IsScalar {
+			text, err := marshalText(value)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.StructFieldName, err)
+			}
+			result[field.Column] = text
+
+			continue
+		}
+
+		result[field.Column] = value.Interface()
+	}
+
+	return result, nil
+}
+
+// FromMap sets the fields of the struct pointed to by dst from a map keyed by resolved column name.
+// Values are assigned directly when assignable, otherwise converted between compatible kinds
+// (e.g. float64 from JSON into an int field); incompatible values return an error naming the field.
+// Each field is read from its column, or else from the first of its Aliases present in data.
+// Decoders registered with WithTypeDecoder run first for fields of their type; otherwise string values
+// for fields implementing encoding.TextUnmarshaler (e.g. net.IP, time.Time) are decoded with UnmarshalText.
+// Nil pointers to embedded or inline structs (e.g. Address *Address `schema:",inline,prefix=address_"`)
+// are allocated on write, through GetValueInit, only once a key of one of their fields is present and
+// its value converts, so they stay nil when data has none of their keys.
+// Ignored, transient and unexported fields are never set, though WithStrictKeys accepts the columns of
+// transient fields as known keys. dst must be a non-nil pointer to a struct.
+func (m *Metadata) FromMap(data map[string]any, dst any, opts ...FromMapOption) error {
+	cfg := &fromMapConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	rv, err := resolveStructRoot(dst, "decode map into %s", true)
+	if err != nil {
+		return err
+	}
+
+	structMeta, err := m.GetStructMetadata(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	if cfg.strictKeys {
+		if err := checkUnknownKeys(data, structMeta); err != nil {
+			return err
+		}
+	}
+
+	for _, field := range structMeta.Fields {
+		if field.Ignored || field.Transient || !field.Exported {
+			continue
+		}
+
+		value, ok := data[field.Column]
+		for _, alias := range field.Aliases {
+			if ok {
+				break
+			}
+			value, ok = data[alias]
+		}
+		if !ok {
+			continue
+		}
+
+		if decode, ok := m.typeDecoder(field.Type); ok && value != nil {
+			value, err = decode(value)
+			if err != nil {
+				return fmt.Errorf("field %s: %w", field.StructFieldName, err)
+			}
+		} else if field.IsTextUnmarshaler {
+			value, err = unmarshalText(value, field.Type)
+			if err != nil {
+				return fmt.Errorf("field %s: %w", field.StructFieldName, err)
+			}
+		}
+
+		coerced, err := coerceValue(value, field.Type)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.StructFieldName, err)
+		}
+
+		target, err := field.GetValueInit(rv)
+		if err != nil {
+			return err
+		}
+		target.Set(coerced)
+	}
+
+	return nil
+}
+
+// typeDecoder returns the decoder registered for typ, falling back to the one for its pointer element type.
+func (m *Metadata) typeDecoder(typ reflect.Type) (typeConverter, bool) {
+	decoders := m.cache.builder.typeDecoders
+	if decode, ok := decoders[typ]; ok {
+		return decode, true
+	}
+	decode, ok := decoders[derefType(typ)]
+
+	return decode, ok
+}
+
+// marshalText encodes the dereferenced field value with its encoding.TextMarshaler implementation.
+// Values of types with a pointer-receiver MarshalText are copied when not addressable.
+func marshalText(value reflect.Value) (string, error) {
+	if !value.Type().Implements(textMarshalerType) {
+		if !value.CanAddr() {
+			ptr := reflect.New(value.Type())
+			ptr.Elem().Set(value)
+			value = ptr.Elem()
+		}
+		value = value.Addr()
+	}
+
+	marshaler, ok := value.Interface().(encoding.TextMarshaler)
+	if !ok {
+		return "", fmt.Errorf("%v does not implement encoding.TextMarshaler", value.Type())
+	}
+
+	text, err := marshaler.MarshalText()
+	if err != nil {
+		return "", err
+	}
+
+	return string(text), nil
+}
+
+// unmarshalText decodes a string value into the non-pointer type behind typ with its
+// encoding.TextUnmarshaler implementation. Other values are returned unchanged for coerceValue.
+func unmarshalText(value any, typ reflect.Type) (any, error) {
+	text, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	ptr := reflect.New(derefType(typ))
+	unmarshaler, ok := ptr.Interface().(encoding.TextUnmarshaler)
+	if !ok {
+		return nil, fmt.Errorf("%v does not implement encoding.TextUnmarshaler", ptr.Type())
+	}
+	if err := unmarshaler.UnmarshalText([]byte(text)); err != nil {
+		return nil, err
+	}
+
+	return ptr.Elem().Interface(), nil
+}
+
+// checkUnknownKeys returns an error listing the map keys that match no column.
+func checkUnknownKeys(data map[string]any, structMeta *StructMetadata) error {
+	var unknown []string
+	for key := range data {
+		if _, ok := structMeta.FieldByColumn(key); !ok {
+			unknown = append(unknown, key)
+		}
+	}
+
+	if len(unknown) == 0 {
+		return nil
+	}
+	slices.Sort(unknown)
+
+	return fmt.Errorf("unknown keys for %v: %s", structMeta.Type, strings.Join(unknown, ", "))
+}
+
+// coerceValue converts value to typ, allocating pointers as needed.
+// A nil value produces the zero value of typ.
+func coerceValue(value any, typ reflect.Type) (reflect.Value, error) {
+	if value == nil {
+		return reflect.Zero(typ), nil
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Type().AssignableTo(typ) {
+		return rv, nil
+	}
+
+	if typ.Kind() == reflect.Pointer {
+		elem, err := coerceValue(value, typ.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(elem)
+
+		return ptr, nil
+	}
+
+	if !isCompatibleKind(rv.Type(), typ) || !rv.Type().ConvertibleTo(typ) {
+		return reflect.Value{}, fmt.Errorf("cannot assign %v to %v", rv.Type(), typ)
+	}
+
+	if err := checkOverflow(rv, typ); err != nil {
+		return reflect.Value{}, err
+	}
+
+	return rv.Convert(typ), nil
+}
+
+// isCompatibleKind reports whether a value of type from may be converted to type to.
+// Conversions are limited to numeric-to-numeric and same-kind conversions, so for example
+// an int is never converted into a string rune.
+func isCompatibleKind(from, to reflect.Type) bool {
+	if categoryOf(from) == CategoryNumeric && categoryOf(to) == CategoryNumeric {
+		return true
+	}
+
+	return from.Kind() == to.Kind()
+}
+
+// checkOverflow returns an error if converting the numeric value rv to typ would lose information.
+func checkOverflow(rv reflect.Value, typ reflect.Type) error {
+	if categoryOf(typ) != CategoryNumeric {
+		return nil
+	}
+
+	target := reflect.New(typ).Elem()
+	switch {
+	case rv.CanFloat():
+		f := rv.Float()
+		if target.CanFloat() {
+			if target.OverflowFloat(f) {
+				return fmt.Errorf("value %v overflows %v", f, typ)
+			}
+
+			return nil
+		}
+		if f != float64(int64(f)) {
+			return fmt.Errorf("value %v is not an integer, cannot assign to %v", f, typ)
+		}
+		if (target.CanInt() && target.OverflowInt(int64(f))) || (target.CanUint() && (f < 0 || target.OverflowUint(uint64(f)))) {
+			return fmt.Errorf("value %v overflows %v", f, typ)
+		}
+	case rv.CanInt():
+		i := rv.Int()
+		if (target.CanInt() && target.OverflowInt(i)) || (target.CanUint() && (i < 0 || target.OverflowUint(uint64(i)))) {
+			return fmt.Errorf("value %v overflows %v", i, typ)
+		}
+	case rv.CanUint():
+		u := rv.Uint()
+		if (target.CanUint() && target.OverflowUint(u)) || (target.CanInt() && (u > 1<<63-1 || target.OverflowInt(int64(u)))) {
+			return fmt.Errorf("value %v overflows %v", u, typ)
+		}
+	}
+
+	return nil
+}
+
+// fieldByIndexAlloc returns the settable field at index path, allocating nil embedded pointers along the way.
+// It returns an error if the field, or a nil embedded pointer that needs allocating, cannot be set
+// (e.g. a pointer to an unexported embedded struct).
+func fieldByIndexAlloc(rv reflect.Value, indexPath []int) (reflect.Value, error) {
+	for i, index := range indexPath {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				if !rv.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot allocate embedded pointer to unexported struct %v", rv.Type().Elem())
+				}
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(index)
+	}
+
+	if !rv.CanSet() {
+		return reflect.Value{}, fmt.Errorf("field is not settable")
+	}
+
+	return rv, nil
+}
+
+// fieldValue returns the dereferenced value of field in rv.
+// It returns false if the field or an embedded pointer on its index path is nil.
+func fieldValue(rv reflect.Value, field FieldMetadata) (reflect.Value, bool) {
+	value, ok := field.GetValueOrZero(rv)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return reflect.Value{}, false
+		}
+		value = value.Elem()
+	}
+
+	return value, true
+}
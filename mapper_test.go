@@ -0,0 +1,584 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type MapperAddress struct {
+	City string `schema:"city"`
+}
+
+type mapperLocation struct {
+	Zip string `schema:"zip"`
+}
+
+type mapperUser struct {
+	ID       int     `schema:"id,pk"`
+	Name     string  `schema:"name"`
+	Nickname *string `schema:"nickname"`
+	Bio      *string `schema:"bio,omitempty"`
+	Age      *int    `schema:"age"`
+	Secret   string  `schema:"-"`
+	*MapperAddress
+}
+
+func TestMetadata_ToMap(t *testing.T) {
+	metadata := NewDefaultMetadata()
+	age := 30
+
+	t.Run("struct value", func(t *testing.T) {
+		result, err := metadata.ToMap(mapperUser{ID: 1, Name: "Alice", Age: &age, Secret: "hidden"})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"id":       1,
+			"name":     "Alice",
+			"nickname": nil,
+			"age":      30,
+			"city":     nil,
+		}, result)
+	})
+
+	t.Run("pointer to struct", func(t *testing.T) {
+		nickname := "Al"
+		bio := "Gopher"
+		result, err := metadata.ToMap(&mapperUser{
+			ID:            2,
+			Nickname:      &nickname,
+			Bio:           &bio,
+			MapperAddress: &MapperAddress{City: "Berlin"},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"id":       2,
+			"name":     "",
+			"nickname": "Al",
+			"bio":      "Gopher",
+			"age":      nil,
+			"city":     "Berlin",
+		}, result)
+	})
+
+	t.Run("omitempty uses encoding/json emptiness", func(t *testing.T) {
+		type Profile struct {
+			Name    string            `schema:"name,omitempty"`
+			Age     int               `schema:"age,omitempty"`
+			Active  bool              `schema:"active,omitempty"`
+			Tags    []string          `schema:"tags,omitempty"`
+			Labels  map[string]string `schema:"labels,omitempty"`
+			Score   *int              `schema:"score,omitempty"`
+			Payload any               `schema:"payload,omitempty"`
+			Title   string            `schema:"title"`
+		}
+
+		result, err := metadata.ToMap(Profile{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"title": ""}, result)
+
+		zero := 0
+		result, err = metadata.ToMap(Profile{Age: 1, Tags: []string{"a"}, Score: &zero})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"age": 1, "tags": []string{"a"}, "score": 0, "title": ""}, result,
+			"a non-nil pointer to a zero value is not empty")
+	})
+
+	t.Run("WithIncludeZeroValues", func(t *testing.T) {
+		type Update struct {
+			ID       int     `schema:"id,pk"`
+			Name     string  `schema:"name,omitempty"`
+			Email    string  `schema:"email,omitempty"`
+			Score    int     `schema:"score,omitempty"`
+			Nickname *string `schema:"nickname,omitempty"`
+			Internal string  `schema:"-"`
+			*MapperAddress
+		}
+		update := Update{ID: 5, Email: "a@example.com", Internal: "x"}
+
+		result, err := metadata.ToMap(update)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"id": 5, "email": "a@example.com", "city": nil}, result)
+
+		result, err = metadata.ToMap(update, WithIncludeZeroValues())
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"id":       5,
+			"name":     "",
+			"email":    "a@example.com",
+			"score":    0,
+			"nickname": nil,
+			"city":     nil,
+		}, result)
+
+		result, err = metadata.ToMap(update)
+		require.NoError(t, err)
+		assert.NotContains(t, result, "name", "the option applies to a single call")
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := metadata.ToMap(42)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot convert int to map")
+
+		_, err = metadata.ToMap(nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot convert nil to map")
+
+		_, err = metadata.ToMap((*mapperUser)(nil))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot convert nil *schema.mapperUser to map")
+	})
+}
+
+func TestMetadata_FromMap(t *testing.T) {
+	metadata := NewDefaultMetadata()
+
+	t.Run("sets fields by column with coercion", func(t *testing.T) {
+		var user mapperUser
+		err := metadata.FromMap(map[string]any{
+			"id":       float64(7), // JSON numbers decode as float64
+			"name":     "Alice",
+			"nickname": "Al",
+			"age":      int64(30),
+			"city":     "Paris",
+			"secret":   "ignored",
+			"unknown":  true,
+		}, &user)
+
+		require.NoError(t, err)
+		assert.Equal(t, 7, user.ID)
+		assert.Equal(t, "Alice", user.Name)
+		require.NotNil(t, user.Nickname)
+		assert.Equal(t, "Al", *user.Nickname)
+		require.NotNil(t, user.Age)
+		assert.Equal(t, 30, *user.Age)
+		require.NotNil(t, user.MapperAddress, "embedded pointer is allocated")
+		assert.Equal(t, "Paris", user.City)
+		assert.Empty(t, user.Secret)
+		assert.Nil(t, user.Bio)
+	})
+
+	t.Run("nil value resets field", func(t *testing.T) {
+		nickname := "Al"
+		user := mapperUser{Nickname: &nickname}
+		require.NoError(t, metadata.FromMap(map[string]any{"nickname": nil}, &user))
+		assert.Nil(t, user.Nickname)
+	})
+
+	t.Run("incompatible values", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			data    map[string]any
+			wantErr string
+		}{
+			{name: "int into string", data: map[string]any{"name": 5}, wantErr: "field Name: cannot assign int to string"},
+			{name: "string into int", data: map[string]any{"id": "5"}, wantErr: "field ID: cannot assign string to int"},
+			{name: "fractional float into int", data: map[string]any{"id": 1.5}, wantErr: "field ID: value 1.5 is not an integer"},
+			{name: "bool into pointer", data: map[string]any{"age": true}, wantErr: "field Age: cannot assign bool to int"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var user mapperUser
+				err := metadata.FromMap(tt.data, &user)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		type small struct {
+			Tiny  int8  `schema:"tiny"`
+			Count uint8 `schema:"count"`
+		}
+
+		err := metadata.FromMap(map[string]any{"tiny": 300}, &small{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "value 300 overflows int8")
+
+		err = metadata.FromMap(map[string]any{"count": -1}, &small{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "value -1 overflows uint8")
+	})
+
+	t.Run("strict keys", func(t *testing.T) {
+		var user mapperUser
+		err := metadata.FromMap(map[string]any{"name": "Alice", "zeta": 1, "alpha": 2, "secret": "x"}, &user, WithStrictKeys())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown keys for schema.mapperUser: alpha, secret, zeta", "ignored fields have no column")
+
+		require.NoError(t, metadata.FromMap(map[string]any{"name": "Alice"}, &user, WithStrictKeys()))
+		assert.Equal(t, "Alice", user.Name)
+	})
+
+	t.Run("nil pointer to unexported embedded struct", func(t *testing.T) {
+		type withLocation struct {
+			*mapperLocation
+		}
+
+		err := metadata.FromMap(map[string]any{"zip": "10115"}, &withLocation{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field Zip: cannot allocate embedded pointer to unexported struct schema.mapperLocation")
+
+		dst := &withLocation{mapperLocation: &mapperLocation{}}
+		require.NoError(t, metadata.FromMap(map[string]any{"zip": "10115"}, dst))
+		assert.Equal(t, "10115", dst.Zip)
+	})
+
+	t.Run("invalid destination", func(t *testing.T) {
+		err := metadata.FromMap(map[string]any{}, mapperUser{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot decode map into schema.mapperUser: expected non-nil pointer to struct")
+
+		err = metadata.FromMap(map[string]any{}, (*mapperUser)(nil))
+		require.Error(t, err)
+
+		err = metadata.FromMap(map[string]any{}, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot decode map into nil")
+	})
+}
+
+func TestMetadata_ToMapFromMap_SkipUnexported(t *testing.T) {
+	type withSecret struct {
+		Name   string `schema:"name"`
+		secret string
+	}
+
+	metadata := NewDefaultMetadata(WithIncludeUnexported())
+
+	result, err := metadata.ToMap(withSecret{Name: "Alice", secret: "s3cr3t"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "Alice"}, result)
+
+	var dst withSecret
+	require.NoError(t, metadata.FromMap(map[string]any{"name": "Bob", "secret": "x"}, &dst))
+	assert.Equal(t, withSecret{Name: "Bob"}, dst)
+}
+
+type mapperToken []byte
+
+type mapperEvent struct {
+	Name      string      `schema:"name"`
+	CreatedAt time.Time   `schema:"created_at"`
+	UpdatedAt *time.Time  `schema:"updated_at"`
+	Token     mapperToken `schema:"token"`
+}
+
+func TestMetadata_TypeConverters(t *testing.T) {
+	timeType := reflect.TypeOf(time.Time{})
+	errBadToken := errors.New("bad token")
+	metadata := NewDefaultMetadata(
+		WithTypeDecoder(timeType, func(v any) (any, error) {
+			s, ok := v.(string)
+			if !ok {
+				return v, nil
+			}
+
+			return time.Parse(time.RFC3339, s)
+		}),
+		WithTypeDecoder(reflect.TypeOf(mapperToken(nil)), func(v any) (any, error) {
+			s, ok := v.(string)
+			if !ok || s == "" {
+				return nil, errBadToken
+			}
+
+			return mapperToken("tok:" + s), nil
+		}),
+		WithTypeEncoder(timeType, func(v any) (any, error) {
+			return v.(time.Time).Format(time.RFC3339), nil
+		}),
+		WithTypeDecoder(nil, nil),
+		WithTypeEncoder(timeType, nil),
+	)
+	created := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	t.Run("decoders run before coercion", func(t *testing.T) {
+		var event mapperEvent
+		err := metadata.FromMap(map[string]any{
+			"name":       "launch",
+			"created_at": "2024-05-01T12:30:00Z",
+			"updated_at": "2024-05-01T12:30:00Z",
+			"token":      "abc",
+		}, &event)
+
+		require.NoError(t, err)
+		assert.Equal(t, "launch", event.Name)
+		assert.True(t, created.Equal(event.CreatedAt))
+		require.NotNil(t, event.UpdatedAt, "decoder for T applies to *T")
+		assert.True(t, created.Equal(*event.UpdatedAt))
+		assert.Equal(t, mapperToken("tok:abc"), event.Token)
+	})
+
+	t.Run("nil bypasses decoder", func(t *testing.T) {
+		event := mapperEvent{UpdatedAt: &created}
+		require.NoError(t, metadata.FromMap(map[string]any{"updated_at": nil}, &event))
+		assert.Nil(t, event.UpdatedAt)
+	})
+
+	t.Run("decoder errors name the field", func(t *testing.T) {
+		var event mapperEvent
+		err := metadata.FromMap(map[string]any{"token": ""}, &event)
+		require.ErrorIs(t, err, errBadToken)
+		assert.Contains(t, err.Error(), "field Token: bad token")
+
+		err = metadata.FromMap(map[string]any{"created_at": "yesterday"}, &event)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field CreatedAt: parsing time")
+	})
+
+	t.Run("encoders", func(t *testing.T) {
+		result, err := metadata.ToMap(mapperEvent{Name: "launch", CreatedAt: created, UpdatedAt: &created})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"name":       "launch",
+			"created_at": "2024-05-01T12:30:00Z",
+			"updated_at": "2024-05-01T12:30:00Z",
+			"token":      mapperToken(nil),
+		}, result)
+
+		result, err = metadata.ToMap(mapperEvent{})
+		require.NoError(t, err)
+		assert.Nil(t, result["updated_at"], "nil pointers are not encoded")
+	})
+
+	t.Run("encoder errors name the field", func(t *testing.T) {
+		failing := NewDefaultMetadata(WithTypeEncoder(timeType, func(v any) (any, error) {
+			return nil, fmt.Errorf("unsupported time %v", v)
+		}))
+		_, err := failing.ToMap(mapperEvent{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field CreatedAt: unsupported time")
+	})
+}
+
+// mapperLevel has pointer-receiver text methods, so non-addressable values must be copied to marshal.
+type mapperLevel int
+
+func (l *mapperLevel) MarshalText() ([]byte, error) {
+	return []byte([]string{"low", "high"}[*l]), nil
+}
+
+func (l *mapperLevel) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "low":
+		*l = 0
+	case "high":
+		*l = 1
+	default:
+		return fmt.Errorf("unknown level %q", text)
+	}
+
+	return nil
+}
+
+type mapperHost struct {
+	Name      string      `schema:"name"`
+	Addr      net.IP      `schema:"addr"`
+	Gateway   *net.IP     `schema:"gateway"`
+	Level     mapperLevel `schema:"level"`
+	CreatedAt time.Time   `schema:"created_at"`
+}
+
+func TestMetadata_TextMarshaling(t *testing.T) {
+	metadata := NewDefaultMetadata()
+	created := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	t.Run("flags", func(t *testing.T) {
+		structMeta, err := metadata.Parse(mapperHost{})
+		require.NoError(t, err)
+
+		for _, name := range []string{"Addr", "Gateway", "Level", "CreatedAt"} {
+			field := structMeta.MustFieldByName(name)
+			assert.True(t, field.IsTextMarshaler, name)
+			assert.True(t, field.IsTextUnmarshaler, name)
+		}
+		name := structMeta.MustFieldByName("Name")
+		assert.False(t, name.IsTextMarshaler || name.IsTextUnmarshaler)
+	})
+
+	t.Run("ToMap marshals text", func(t *testing.T) {
+		gateway := net.ParseIP("10.0.0.1")
+		host := mapperHost{Name: "db", Addr: net.ParseIP("10.0.0.7"), Gateway: &gateway, Level: 1, CreatedAt: created}
+
+		result, err := metadata.ToMap(host)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"name":       "db",
+			"addr":       "10.0.0.7",
+			"gateway":    "10.0.0.1",
+			"level":      "high",
+			"created_at": created,
+		}, result, "scalars such as time.Time keep their value")
+	})
+
+	t.Run("FromMap unmarshals text", func(t *testing.T) {
+		var host mapperHost
+		err := metadata.FromMap(map[string]any{
+			"addr":       "192.168.1.1",
+			"gateway":    "192.168.1.254",
+			"level":      "HIGH",
+			"created_at": "2024-05-01T12:30:00Z",
+		}, &host)
+
+		require.NoError(t, err)
+		assert.Equal(t, "192.168.1.1", host.Addr.String())
+		require.NotNil(t, host.Gateway)
+		assert.Equal(t, "192.168.1.254", host.Gateway.String())
+		assert.Equal(t, mapperLevel(1), host.Level)
+		assert.True(t, created.Equal(host.CreatedAt))
+	})
+
+	t.Run("non-text values are coerced", func(t *testing.T) {
+		var host mapperHost
+		addr := []byte{10, 0, 0, 7}
+		require.NoError(t, metadata.FromMap(map[string]any{"addr": addr, "level": float64(1), "created_at": created}, &host))
+		assert.Equal(t, net.IP(addr), host.Addr, "only strings are unmarshaled as text")
+		assert.Equal(t, mapperLevel(1), host.Level)
+		assert.Equal(t, created, host.CreatedAt)
+	})
+
+	t.Run("unmarshal errors name the field", func(t *testing.T) {
+		var host mapperHost
+		err := metadata.FromMap(map[string]any{"level": "medium"}, &host)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `field Level: unknown level "medium"`)
+	})
+}
+
+func TestMetadata_FromMap_AllocatesInlinePointers(t *testing.T) {
+	type Address struct {
+		Street string `schema:"street"`
+		City   string `schema:"city"`
+		Zip    int    `schema:"zip"`
+	}
+	type Customer struct {
+		Name    string   `schema:"name"`
+		Address *Address `schema:",inline,prefix=address_"`
+	}
+
+	metadata := NewDefaultMetadata()
+
+	t.Run("keys absent", func(t *testing.T) {
+		var customer Customer
+		require.NoError(t, metadata.FromMap(map[string]any{"name": "Ada", "city": "London"}, &customer))
+
+		assert.Equal(t, "Ada", customer.Name)
+		assert.Nil(t, customer.Address, "no address_ key, no allocation")
+	})
+
+	t.Run("keys partially present", func(t *testing.T) {
+		var customer Customer
+		require.NoError(t, metadata.FromMap(map[string]any{"name": "Ada", "address_city": "London"}, &customer))
+
+		require.NotNil(t, customer.Address)
+		assert.Equal(t, Address{City: "London"}, *customer.Address, "absent fields stay zero")
+	})
+
+	t.Run("existing pointer is reused", func(t *testing.T) {
+		existing := &Address{Street: "Baker St"}
+		customer := Customer{Address: existing}
+		require.NoError(t, metadata.FromMap(map[string]any{"address_zip": 12}, &customer))
+
+		assert.Same(t, existing, customer.Address)
+		assert.Equal(t, Address{Street: "Baker St", Zip: 12}, *customer.Address)
+	})
+
+	t.Run("no allocation when the value does not convert", func(t *testing.T) {
+		var customer Customer
+		err := metadata.FromMap(map[string]any{"address_zip": "twelve"}, &customer)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field Zip")
+		assert.Nil(t, customer.Address)
+	})
+}
+
+func TestMetadata_FromMap_Aliases(t *testing.T) {
+	type Event struct {
+		UserID int    `schema:"user_id,alias=userId,alias=id"`
+		Name   string `schema:"name,alias=,alias=title"`
+	}
+
+	metadata := NewDefaultMetadata()
+	structMeta, err := metadata.Parse(Event{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"userId", "id"}, structMeta.MustFieldByName("UserID").Aliases)
+	assert.Equal(t, []string{"title"}, structMeta.MustFieldByName("Name").Aliases, "empty aliases are skipped")
+
+	tests := []struct {
+		name string
+		data map[string]any
+		want Event
+	}{
+		{name: "primary column", data: map[string]any{"user_id": 1}, want: Event{UserID: 1}},
+		{name: "first alias", data: map[string]any{"userId": 2}, want: Event{UserID: 2}},
+		{name: "second alias", data: map[string]any{"id": 3, "title": "t"}, want: Event{UserID: 3, Name: "t"}},
+		{name: "primary column wins", data: map[string]any{"id": 3, "user_id": 1, "userId": 2}, want: Event{UserID: 1}},
+		{name: "earlier alias wins", data: map[string]any{"id": 3, "userId": 2}, want: Event{UserID: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var event Event
+			require.NoError(t, metadata.FromMap(tt.data, &event))
+			assert.Equal(t, tt.want, event)
+		})
+	}
+
+	t.Run("aliases are known keys in strict mode", func(t *testing.T) {
+		var event Event
+		require.NoError(t, metadata.FromMap(map[string]any{"id": 3, "title": "t"}, &event, WithStrictKeys()))
+
+		err := metadata.FromMap(map[string]any{"user": "x"}, &event, WithStrictKeys())
+		require.Error(t, err)
+	})
+
+	t.Run("FieldByColumn falls back to aliases", func(t *testing.T) {
+		field, ok := structMeta.FieldByColumn("userId")
+		require.True(t, ok)
+		assert.Equal(t, "UserID", field.StructFieldName)
+
+		field, ok = structMeta.FieldByColumn("user_id")
+		require.True(t, ok)
+		assert.Equal(t, "UserID", field.StructFieldName)
+
+		_, ok = structMeta.FieldByColumn("user")
+		assert.False(t, ok)
+	})
+}
+
+func TestMetadata_ToMapFromMap_Transient(t *testing.T) {
+	type Session struct {
+		ID      string `schema:"id"`
+		Token   string `schema:"token,transient"`
+		Counter int    `schema:",transient"`
+	}
+
+	metadata := NewDefaultMetadata()
+	structMeta, err := metadata.Parse(Session{})
+	require.NoError(t, err)
+	require.Len(t, structMeta.Fields, 3, "transient fields stay visible")
+	counter := structMeta.MustFieldByName("Counter")
+	assert.True(t, counter.Transient)
+	assert.Equal(t, "counter", counter.Column, "transient fields keep their column")
+
+	result, err := metadata.ToMap(Session{ID: "s1", Token: "secret", Counter: 3}, WithIncludeZeroValues())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"id": "s1"}, result)
+
+	var session Session
+	err = metadata.FromMap(map[string]any{"id": "s2", "token": "secret", "counter": 4}, &session, WithStrictKeys())
+	require.NoError(t, err)
+	assert.Equal(t, Session{ID: "s2"}, session)
+}
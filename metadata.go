@@ -1,9 +1,14 @@
 package schema
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 )
 
 // Metadata is a separate component for metadata operations (tag parsing, metadata building, caching).
@@ -15,10 +20,10 @@ type Metadata struct {
 	cache *metadataCache
 }
 
-// NewMetadata creates a new Metadata with the given registry.
-func NewMetadata(registry *TagParserRegistry) *Metadata {
+// NewMetadata creates a new Metadata with the given registry and options.
+func NewMetadata(registry *TagParserRegistry, opts ...MetadataOption) *Metadata {
 	// Create builder with registry
-	builder := newMetadataBuilder(registry)
+	builder := newMetadataBuilder(registry, opts...)
 
 	// Create internal cache with builder
 	cache := newMetadataCache(builder)
@@ -29,17 +34,82 @@ func NewMetadata(registry *TagParserRegistry) *Metadata {
 }
 
 // NewDefaultMetadata creates a new Metadata with default parsers (schema and body).
-func NewDefaultMetadata() *Metadata {
+func NewDefaultMetadata(opts ...MetadataOption) *Metadata {
 	registry := NewDefaultTagParserRegistry()
 
-	return NewMetadata(registry)
+	return NewMetadata(registry, opts...)
 }
 
-// GetStructMetadata retrieves or builds struct metadata for the given type.
+// GetStructMetadata retrieves or builds struct metadata for the given struct type.
+// Non-struct types return an error; use ParseType to also accept pointer-to-struct types.
 func (m *Metadata) GetStructMetadata(typ reflect.Type) (*StructMetadata, error) {
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot build struct metadata for %v: expected struct type", typ)
+	}
+
 	return m.cache.get(typ)
 }
 
+// ParseType returns the metadata for a struct type or pointer-to-struct type.
+// For pointer types the result has IsPointerRoot set; it is a cached view sharing Type, Fields and
+// the lookup indexes with the struct type's metadata, so both describe the same fields.
+func (m *Metadata) ParseType(typ reflect.Type) (*StructMetadata, error) {
+	if typ == nil {
+		return nil, errors.New("cannot parse nil type")
+	}
+
+	structMeta, err := m.GetStructMetadata(derefType(typ))
+	if err != nil || typ.Kind() != reflect.Pointer {
+		return structMeta, err
+	}
+
+	return m.cache.pointerView(structMeta), nil
+}
+
+// Parse returns the metadata for the type of v, which must be a struct or pointer to struct.
+// A typed nil pointer is accepted since only its type is needed.
+func (m *Metadata) Parse(v any) (*StructMetadata, error) {
+	if v == nil {
+		return nil, errors.New("cannot parse nil value")
+	}
+
+	return m.ParseType(reflect.TypeOf(v))
+}
+
+// ParseAll parses the types of values, e.g. every model at application startup, populating the cache.
+// The result maps each successfully parsed struct type (pointers dereferenced) to the metadata
+// GetStructMetadata returns for it, so IsPointerRoot is never set, and is
+// returned even if some values fail, so all problems can be reported at once. The error joins one
+// error per failed value, naming its type (or its position for nil values) and the reason.
+func (m *Metadata) ParseAll(values ...any) (map[reflect.Type]*StructMetadata, error) {
+	result := make(map[reflect.Type]*StructMetadata, len(values))
+	var errs []error
+	for i, v := range values {
+		structMeta, err := m.Parse(v)
+		if err == nil && structMeta.IsPointerRoot {
+			structMeta, err = m.GetStructMetadata(structMeta.Type)
+		}
+		if err != nil {
+			if v == nil {
+				errs = append(errs, fmt.Errorf("value %d: %w", i, err))
+			} else {
+				errs = append(errs, fmt.Errorf("%v: %w", reflect.TypeOf(v), err))
+			}
+
+			continue
+		}
+		result[structMeta.Type] = structMeta
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// ClearCache removes all cached struct metadata, forcing the next lookup of each type to rebuild it.
+// Useful in tests and long-running processes that load types dynamically.
+func (m *Metadata) ClearCache() {
+	m.cache.clear()
+}
+
 // GetTagMetadata is a package-level generic function for type-safe access to tag metadata.
 // Usage: GetTagMetadata[*SchemaMetadata](field, "schema").
 func GetTagMetadata[T any](f *FieldMetadata, tagName string) (T, bool) {
@@ -67,9 +137,28 @@ func (f *FieldMetadata) HasTag(tagName string) bool {
 }
 
 type StructMetadata struct {
-	Type         reflect.Type
-	Fields       []FieldMetadata
-	fieldsByName map[string]*FieldMetadata
+	Type reflect.Type
+	// Name and PkgPath are the type's name and import path, e.g. "User" and "github.com/acme/models".
+	// Both are empty for anonymous struct types. For instantiated generic types Name includes the
+	// type arguments as reflect reports them, e.g. "Page[github.com/acme/models.User]".
+	Name    string
+	PkgPath string
+	// IsPointerRoot is set on metadata returned by ParseType or Parse for a pointer-to-struct type, such
+	// as *User, recording that callers hold pointers: those are needed to populate values (FromMap,
+	// SetValue, ApplyDefaults). Type is always the struct type.
+	IsPointerRoot  bool
+	Fields         []FieldMetadata
+	fieldsByName   map[string]*FieldMetadata
+	fieldsByColumn map[string]*FieldMetadata
+	// fieldsByFoldedColumn is keyed by lowercase column, nil unless WithCaseInsensitiveColumns is set
+	fieldsByFoldedColumn map[string]*FieldMetadata
+	sqlTypeMapper        SQLTypeMapper
+	columnValidator      func(name string) error
+	foldColumns          bool
+	// immutable is set by WithImmutable, see IsImmutable
+	immutable bool
+	// warnings are collected with WithCollectWarnings, see Warnings
+	warnings []string
 }
 
 // NewStructMetadata creates a new struct metadata from a type and fields.
@@ -77,32 +166,117 @@ type StructMetadata struct {
 func NewStructMetadata(typ reflect.Type, fields []FieldMetadata) (*StructMetadata, error) {
 	// Validate all fields and collect errors
 	var errs []error
-	for i, field := range fields {
+	for _, field := range fields {
 		if err := validateField(field); err != nil {
-			errs = append(errs, fmt.Errorf("field[%d] %q: %w", i, field.StructFieldName, err))
+			errs = append(errs, err)
 		}
 	}
 
+	errs = append(errs, validateIndexPaths(fields)...)
+
 	if len(errs) > 0 {
 		return nil, fmt.Errorf("validation failed: %w", errors.Join(errs...))
 	}
 
-	// Build map for O(1) lookup by StructFieldName
-	fieldsByName := make(map[string]*FieldMetadata, len(fields))
 	for i := range fields {
-		fieldsByName[fields[i].StructFieldName] = &fields[i]
+		fields[i].IsNested = len(fields[i].IndexPath) > 1
+	}
+
+	structMeta := &StructMetadata{
+		Type:   typ,
+		Fields: fields,
+	}
+	if typ != nil {
+		structMeta.Name = typ.Name()
+		structMeta.PkgPath = typ.PkgPath()
+	}
+	if len(fields) >= indexedLookupMinFields {
+		structMeta.buildIndexes()
+	}
+
+	return structMeta, nil
+}
+
+// indexedLookupMinFields is the field count from which name and column lookups use maps.
+// With 3 fields a linear scan over Fields is as fast as a map lookup, while by 8 fields the maps win,
+// and building them costs several allocations (see BenchmarkStructMetadata_Lookup and
+// BenchmarkStructMetadata_BuildIndexes). Smaller structs therefore skip the maps.
+const indexedLookupMinFields = 8
+
+// buildIndexes builds the maps for O(1) lookup by StructFieldName and resolved column.
+// The first field wins for both, as with the linear scan.
+func (m *StructMetadata) buildIndexes() {
+	m.fieldsByName = make(map[string]*FieldMetadata, len(m.Fields))
+	m.fieldsByColumn = make(map[string]*FieldMetadata, len(m.Fields))
+	for i := range m.Fields {
+		field := &m.Fields[i]
+		if _, exists := m.fieldsByName[field.StructFieldName]; !exists {
+			m.fieldsByName[field.StructFieldName] = field
+		}
+		if field.Column == "" {
+			continue
+		}
+		if _, exists := m.fieldsByColumn[field.Column]; !exists {
+			m.fieldsByColumn[field.Column] = field
+		}
 	}
+}
+
+// lookupName returns the first field named name, using the index when built.
+func (m *StructMetadata) lookupName(name string) (*FieldMetadata, bool) {
+	if m.fieldsByName != nil {
+		field, exists := m.fieldsByName[name]
 
-	return &StructMetadata{
-		Type:         typ,
-		Fields:       fields,
-		fieldsByName: fieldsByName,
-	}, nil
+		return field, exists
+	}
+
+	for i := range m.Fields {
+		if m.Fields[i].StructFieldName == name {
+			return &m.Fields[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// lookupColumn returns the first field whose column is col, using the index when built.
+func (m *StructMetadata) lookupColumn(col string) (*FieldMetadata, bool) {
+	if m.fieldsByColumn != nil {
+		field, exists := m.fieldsByColumn[col]
+
+		return field, exists
+	}
+
+	if col == "" {
+		return nil, false
+	}
+	for i := range m.Fields {
+		if m.Fields[i].Column == col {
+			return &m.Fields[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// lookupAlias returns the first field with alias among its Aliases. Aliases are rare and only
+// consulted when no column matches, so they are scanned rather than indexed.
+func (m *StructMetadata) lookupAlias(alias string) (*FieldMetadata, bool) {
+	if alias == "" {
+		return nil, false
+	}
+	for i := range m.Fields {
+		if slices.Contains(m.Fields[i].Aliases, alias) {
+			return &m.Fields[i], true
+		}
+	}
+
+	return nil, false
 }
 
 // Field returns FieldMetadata by field name.
 func (m *StructMetadata) Field(fieldName string) (*FieldMetadata, bool) {
-	field, exists := m.fieldsByName[fieldName]
+	field, exists := m.lookupName(fieldName)
 	if !exists {
 		return nil, false
 	}
@@ -110,37 +284,716 @@ func (m *StructMetadata) Field(fieldName string) (*FieldMetadata, bool) {
 	return field, true
 }
 
+// FieldByName returns a copy of the FieldMetadata for the given struct field name.
+// The lookup is case-sensitive; it returns the zero FieldMetadata and false if the field is not present.
+func (m *StructMetadata) FieldByName(name string) (FieldMetadata, bool) {
+	field, exists := m.lookupName(name)
+	if !exists {
+		return FieldMetadata{}, false
+	}
+
+	return *field, true
+}
+
+// MustFieldByName is like FieldByName but panics if the field is not present.
+func (m *StructMetadata) MustFieldByName(name string) FieldMetadata {
+	field, ok := m.FieldByName(name)
+	if !ok {
+		panic(fmt.Sprintf("schema: field %q not found in %v", name, m.Type))
+	}
+
+	return field
+}
+
+// FieldByColumn returns a copy of the FieldMetadata whose resolved column name matches col.
+// A column that matches no field exactly is matched against field Aliases (the first field declaring
+// it wins) and then, with WithCaseInsensitiveColumns, against columns ignoring case.
+// It returns the zero FieldMetadata and false if no field maps to the column.
+func (m *StructMetadata) FieldByColumn(col string) (FieldMetadata, bool) {
+	field, exists := m.lookupColumn(col)
+	if !exists {
+		field, exists = m.lookupAlias(col)
+	}
+	if !exists && m.fieldsByFoldedColumn != nil {
+		field, exists = m.fieldsByFoldedColumn[strings.ToLower(col)]
+	}
+	if !exists {
+		return FieldMetadata{}, false
+	}
+
+	return *field, true
+}
+
+// FieldByPath returns a copy of the FieldMetadata at the dotted path of Go struct field names, such as
+// "Address.City", regardless of the field's column. A segment may name a struct whose fields were
+// flattened into this metadata (embedded, or with the inline option), a field promoted from one (so
+// "City" also finds Address.City when Address is embedded), or a field with ElemMetadata (see
+// WithNestedMetadata), whose fields the following segments resolve. It returns the zero FieldMetadata
+// and false if any segment does not exist or the path is empty.
+func (m *StructMetadata) FieldByPath(path string) (FieldMetadata, bool) {
+	segments := strings.Split(path, ".")
+	if slices.Contains(segments, "") {
+		return FieldMetadata{}, false
+	}
+
+	field, ok := m.fieldByPath(segments)
+	if !ok {
+		return FieldMetadata{}, false
+	}
+
+	return *field, true
+}
+
+// fieldByPath resolves segments, trying the first one as a field name before full flattened paths.
+func (m *StructMetadata) fieldByPath(segments []string) (*FieldMetadata, bool) {
+	if field, ok := m.lookupName(segments[0]); ok {
+		if len(segments) == 1 {
+			return field, true
+		}
+		if field.ElemMetadata != nil {
+			if leaf, ok := field.ElemMetadata.fieldByPath(segments[1:]); ok {
+				return leaf, true
+			}
+		}
+	}
+
+	for i := range m.Fields {
+		field := &m.Fields[i]
+		n := len(field.IndexPath)
+		if n < 2 || n > len(segments) || !m.matchesGoPath(field, segments[:n]) {
+			continue
+		}
+		if n == len(segments) {
+			return field, true
+		}
+		if field.ElemMetadata != nil {
+			if leaf, ok := field.ElemMetadata.fieldByPath(segments[n:]); ok {
+				return leaf, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// matchesGoPath reports whether the Go field names along field's IndexPath, starting at m.Type,
+// equal names. It is false for metadata without a type or with an index path it cannot follow.
+func (m *StructMetadata) matchesGoPath(field *FieldMetadata, names []string) bool {
+	if m.Type == nil || names[len(names)-1] != field.StructFieldName {
+		return false
+	}
+
+	typ := m.Type
+	for i, idx := range field.IndexPath[:len(field.IndexPath)-1] {
+		if typ.Kind() != reflect.Struct || idx < 0 || idx >= typ.NumField() {
+			return false
+		}
+		structField := typ.Field(idx)
+		if structField.Name != names[i] {
+			return false
+		}
+		typ = derefType(structField.Type)
+	}
+
+	return true
+}
+
+// indexFoldedColumns builds the case-insensitive column index.
+// It returns an error for each field whose column collides with another field's column ignoring case;
+// the index is built regardless, with the first such field winning.
+func (m *StructMetadata) indexFoldedColumns() error {
+	var errs []error
+	folded := make(map[string]*FieldMetadata, len(m.Fields))
+	for i := range m.Fields {
+		field := &m.Fields[i]
+		if field.Column == "" {
+			continue
+		}
+
+		key := strings.ToLower(field.Column)
+		if first, exists := folded[key]; exists {
+			errs = append(errs, newFieldError(field.StructFieldName, ErrDuplicateColumn,
+				"column %q collides case-insensitively with column %q of field %q", field.Column, first.Column, first.StructFieldName))
+
+			continue
+		}
+		folded[key] = field
+	}
+
+	m.fieldsByFoldedColumn = folded
+
+	return errors.Join(errs...)
+}
+
+// PrimaryKeys returns the primary key fields in declaration order, supporting composite keys.
+// It returns an empty (non-nil) slice if no field is marked as primary key.
+func (m *StructMetadata) PrimaryKeys() []FieldMetadata {
+	keys := make([]FieldMetadata, 0, 1)
+	for _, field := range m.Fields {
+		if field.IsPrimaryKey {
+			keys = append(keys, field)
+		}
+	}
+
+	return keys
+}
+
+// FieldsSortedByColumn returns a new slice of the fields sorted lexically by resolved column name,
+// leaving Fields in declaration order. Ties (e.g. ignored fields, which have no column) are ordered
+// by index path, so the result is deterministic.
+func (m *StructMetadata) FieldsSortedByColumn() []FieldMetadata {
+	sorted := slices.Clone(m.Fields)
+	slices.SortStableFunc(sorted, func(a, b FieldMetadata) int {
+		if c := strings.Compare(a.Column, b.Column); c != 0 {
+			return c
+		}
+
+		return slices.Compare(a.IndexPath, b.IndexPath)
+	})
+
+	return sorted
+}
+
+// FieldsOrdered returns a new slice of the fields sorted for output by the "order=N" tag option,
+// leaving Fields in declaration order: fields with an order come first by ascending Order (ties by
+// Index, then index path), followed by the fields without one in declaration order.
+func (m *StructMetadata) FieldsOrdered() []FieldMetadata {
+	sorted := slices.Clone(m.Fields)
+	slices.SortStableFunc(sorted, func(a, b FieldMetadata) int {
+		switch {
+		case a.HasOrder != b.HasOrder:
+			if a.HasOrder {
+				return -1
+			}
+
+			return 1
+		case !a.HasOrder:
+			return 0
+		}
+		if c := cmp.Compare(a.Order, b.Order); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Index, b.Index); c != 0 {
+			return c
+		}
+
+		return slices.Compare(a.IndexPath, b.IndexPath)
+	})
+
+	return sorted
+}
+
+// Clone returns a deep copy of the metadata that is detached from the cache: the Fields slice,
+// each field's slices and maps (IndexPath, Tag.Options, Options, Flags, TagMetadata, Extra) and the lookup
+// indexes are copied, so the clone can be mutated (e.g. renaming a column for a one-off query) without
+// affecting the shared instance. TagMetadata and Extra values and ElemMetadata are shared with the original.
+// The lookup indexes point into the clone's Fields, so call Reindex after appending to or replacing
+// that slice, or after changing field names or columns.
+// The clone of metadata parsed with WithImmutable is mutable, but its ElemMetadata stays immutable:
+// clone nested metadata separately before changing it.
+func (m *StructMetadata) Clone() *StructMetadata {
+	clone := *m
+	clone.immutable = false
+	clone.Fields = make([]FieldMetadata, len(m.Fields))
+	for i := range m.Fields {
+		clone.Fields[i] = m.Fields[i].clone()
+	}
+
+	clone.fieldsByName, clone.fieldsByColumn, clone.fieldsByFoldedColumn = nil, nil, nil
+	if m.fieldsByName != nil {
+		clone.buildIndexes()
+	}
+	if m.fieldsByFoldedColumn != nil {
+		// The original was already checked for collisions, so this cannot fail
+		_ = clone.indexFoldedColumns()
+	}
+
+	return &clone
+}
+
+// Warnings returns the non-fatal problems found while parsing, such as a field without a tag or with an
+// unknown tag option, one message per problem in field declaration order, e.g.
+// `field "Email": unknown tag option "uniq"`. Warnings are only collected with WithCollectWarnings;
+// otherwise, and when there are none, it returns nil. Nested metadata has its own warnings.
+func (m *StructMetadata) Warnings() []string {
+	return slices.Clone(m.warnings)
+}
+
+// IsImmutable reports whether the metadata was parsed with WithImmutable, in which case Reindex and
+// SetExtra on its fields refuse to mutate it. Clone returns a mutable copy.
+func (m *StructMetadata) IsImmutable() bool {
+	return m.immutable
+}
+
+// Reindex rebuilds the name and column lookups, including the case-insensitive one when enabled, and
+// recomputes IsNested from each IndexPath, from the current Fields slice. It must be called after any manual mutation of the fields, such as renaming
+// a column on a Clone, or FieldByName and FieldByColumn may return stale results. Mutate clones only:
+// metadata from the cache is shared. The error reports columns that now collide ignoring case;
+// lookups then resolve to the first such field. It returns ErrImmutableMetadata, without changing
+// anything, on metadata parsed with WithImmutable.
+func (m *StructMetadata) Reindex() error {
+	if m.immutable {
+		return ErrImmutableMetadata
+	}
+	for i := range m.Fields {
+		m.Fields[i].IsNested = len(m.Fields[i].IndexPath) > 1
+	}
+	m.fieldsByName, m.fieldsByColumn = nil, nil
+	if len(m.Fields) >= indexedLookupMinFields {
+		m.buildIndexes()
+	}
+	if m.fieldsByFoldedColumn != nil {
+		return m.indexFoldedColumns()
+	}
+
+	return nil
+}
+
+// clone returns a copy of the field with its own slices and maps.
+func (f *FieldMetadata) clone() FieldMetadata {
+	c := *f
+	c.IndexPath = slices.Clone(f.IndexPath)
+	c.Aliases = slices.Clone(f.Aliases)
+	c.Tag.Options = slices.Clone(f.Tag.Options)
+	c.Options = maps.Clone(f.Options)
+	c.Flags = maps.Clone(f.Flags)
+	c.TagMetadata = maps.Clone(f.TagMetadata)
+	c.Extra = maps.Clone(f.Extra)
+	c.immutable = false
+
+	return c
+}
+
+// SetExtra stores val under key in Extra, allocating the map on first use. Call it on the field stored
+// in StructMetadata (via Field or Range) rather than on a copy returned by FieldByName, and note that
+// cached metadata is shared: set extras once, before concurrent use, or on a Clone.
+// It panics on fields of metadata parsed with WithImmutable, including copies of them.
+func (f *FieldMetadata) SetExtra(key string, val any) {
+	if f.immutable {
+		panic(fmt.Sprintf("schema: SetExtra on field %q of immutable metadata, Clone it first", f.StructFieldName))
+	}
+	if f.Extra == nil {
+		f.Extra = make(map[string]any)
+	}
+	f.Extra[key] = val
+}
+
+// GetExtra returns the value stored under key in Extra and whether it exists.
+func (f *FieldMetadata) GetExtra(key string) (any, bool) {
+	val, ok := f.Extra[key]
+
+	return val, ok
+}
+
+// Len returns the number of fields.
+func (m *StructMetadata) Len() int {
+	return len(m.Fields)
+}
+
+// Has reports whether a field with the given struct field name exists.
+// Like FieldByName, the lookup is case-sensitive.
+func (m *StructMetadata) Has(name string) bool {
+	_, exists := m.lookupName(name)
+
+	return exists
+}
+
+// Range calls fn for each field in declaration order with a pointer into the internal Fields slice,
+// avoiding per-field copies. Iteration stops when fn returns false.
+// Callers must treat the pointer as read-only and must not retain it beyond the call if the
+// metadata could be mutated.
+func (m *StructMetadata) Range(fn func(f *FieldMetadata) bool) {
+	for i := range m.Fields {
+		if !fn(&m.Fields[i]) {
+			return
+		}
+	}
+}
+
+// Walk traverses the metadata tree depth-first, calling fn for every field of m and, after each field
+// with ElemMetadata (see WithNestedMetadata), for the fields of that nested metadata. path holds the
+// struct field names from the root down to f, e.g. ["Author", "Address", "City"]; it is reused between
+// calls, so copy it to retain it. The walk stops as soon as fn returns false. Like recursive parsing,
+// it tracks the struct types being visited: a field referring back to one of them (e.g. Next *Node)
+// is reported but not descended into, so cyclic types terminate.
+func (m *StructMetadata) Walk(fn func(path []string, f FieldMetadata) bool) {
+	m.walk(nil, map[reflect.Type]bool{}, fn)
+}
+
+// walk visits the fields of m below path. inProgress holds the types on the current path.
+// It returns false once fn has stopped the walk.
+func (m *StructMetadata) walk(path []string, inProgress map[reflect.Type]bool, fn func(path []string, f FieldMetadata) bool) bool {
+	inProgress[m.Type] = true
+	defer delete(inProgress, m.Type)
+
+	for i := range m.Fields {
+		field := &m.Fields[i]
+		fieldPath := append(path, field.StructFieldName) //nolint:gocritic // Deliberately reuses path's backing array
+		if !fn(fieldPath, *field) {
+			return false
+		}
+
+		if field.ElemMetadata == nil || inProgress[field.ElemMetadata.Type] {
+			continue
+		}
+		if !field.ElemMetadata.walk(fieldPath, inProgress, fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Values of FieldMetadata.SourceTag for columns not named by a tag.
+const (
+	// SourceNaming marks a column derived by the naming strategy.
+	SourceNaming = "naming"
+	// SourceOverride marks a column set with WithColumnOverrides.
+	SourceOverride = "override"
+)
+
 // FieldMetadata represents a cached struct field metadata.
 // It can represent both parameter fields (schema tag) and body fields (body tag).
 type FieldMetadata struct {
 	// StructFieldName is the name of the struct field in Go source code.
 	StructFieldName string
-	// Index is the field index in the struct (used for reflection-based field access).
+	// Index is the field index in its declaring struct (used for reflection-based field access).
+	// For fields promoted from embedded structs it is the index within the embedded struct.
 	Index int
-	// Embedded indicates whether this field is an embedded/anonymous struct field.
+	// IndexPath is the index sequence from the root struct, suitable for reflect.Value.FieldByIndex.
+	// For fields declared directly on the root struct it is []int{Index}.
+	IndexPath []int
+	// IsNested indicates the field is promoted from an embedded struct, i.e. IndexPath has more than
+	// one element. Value access for other fields uses reflect.Value.Field(Index) instead of walking
+	// IndexPath, which saves roughly 5-10% per GetValue or SetValue on flat structs (see
+	// BenchmarkFieldMetadata_GetValue). NewStructMetadata sets it from IndexPath.
+	IsNested bool
+	// Embedded indicates whether this field is an embedded/anonymous field that was not flattened
+	// (e.g. an embedded non-struct type, or a struct beyond WithMaxEmbedDepth). Other embedded structs
+	// are flattened into their promoted fields.
 	Embedded bool
-	// Type is the reflect.Type of the field.
+	// Type is the reflect.Type of the field, as declared.
 	Type reflect.Type
+	// StructField is the original reflect.StructField, giving access to arbitrary tags and PkgPath.
+	StructField reflect.StructField
+	// Exported indicates the field is exported. Unexported fields are only present with
+	// WithIncludeUnexported and cannot be read or written via reflection.
+	Exported bool
+	// IsPointer indicates the declared type is a pointer (e.g. *string, **int).
+	IsPointer bool
+	// IsSlice indicates the underlying non-pointer type is a slice (e.g. []Item).
+	IsSlice bool
+	// IsArray indicates the underlying non-pointer type is an array (e.g. [16]byte).
+	IsArray bool
+	// ArrayLen is the length of the array for array fields (16 for [16]byte and *[16]byte), for consumers
+	// that allocate fixed-size buffers. It is 0 for slices and all other types.
+	ArrayLen int
+	// IsMap indicates the underlying non-pointer type is a map (e.g. map[string]int).
+	// It agrees with Category returning CategoryMap.
+	IsMap bool
+	// KeyType and ValueType are the key and value types of map fields; nil for non-map fields.
+	KeyType   reflect.Type
+	ValueType reflect.Type
+	// IsStruct indicates the underlying non-pointer type is a struct (e.g. Address or *Address).
+	IsStruct bool
+	// IsInterface indicates the underlying non-pointer type is an interface (e.g. any, io.Reader).
+	// Such fields are never descended into; serializers should inspect the dynamic value at runtime.
+	// Category reports CategoryOther for them.
+	IsInterface bool
+	// IsScalar indicates the type (or the type behind its pointers) is registered as a scalar,
+	// such as time.Time (see WithScalarTypes), or implements sql.Scanner or driver.Valuer.
+	// Scalar fields are never descended into, so sql.NullString is not flattened.
+	IsScalar bool
+	// IsScanner and IsValuer indicate the underlying non-pointer type T, or *T, implements
+	// sql.Scanner or driver.Valuer respectively. They are false for interface types.
+	IsScanner bool
+	IsValuer  bool
+	// IsTextMarshaler and IsTextUnmarshaler indicate T or *T implements encoding.TextMarshaler or
+	// encoding.TextUnmarshaler (e.g. net.IP, time.Time), which ToMap and FromMap use for text conversion.
+	// They are false for interface types.
+	IsTextMarshaler   bool
+	IsTextUnmarshaler bool
+	// ElemType is the element type for slices and arrays (Item for []Item and *[]Item).
+	// For other fields it is the underlying non-pointer type, walking down all pointer levels
+	// (string for *string and **string); for non-pointer fields it equals Type.
+	ElemType reflect.Type
+	// ElemMetadata is the parsed metadata of the struct behind ElemType (pointers unwrapped).
+	// It is only populated when WithNestedMetadata is enabled and is nil for non-struct types.
+	ElemMetadata *StructMetadata
+	// Tag is the parsed name and options of the builder's tag key (see WithTagKey).
+	Tag FieldTag
+	// Ignored indicates the field's tag is "-" and the field should be skipped. Its tag is not
+	// interpreted and it has no column (compare Transient).
+	Ignored bool
+	// Column is the resolved column name: the tag name if present, otherwise the name
+	// derived by the naming strategy (see WithNamingStrategy). Empty for ignored fields.
+	Column string
+	// SourceTag records what supplied Column: the tag key whose name was used (the primary key, e.g.
+	// "schema", or the fallback key, e.g. "json"; see WithTagFallback), SourceNaming when the naming
+	// strategy derived it, or SourceOverride for WithColumnOverrides. Empty for ignored fields.
+	SourceTag string
+	// IsPrimaryKey indicates the tag contains the "primaryKey" (or "pk") option.
+	IsPrimaryKey bool
+	// ReadOnly indicates the tag contains the "readonly" (or "->") option, marking a database-generated
+	// column (serial ID, computed column) excluded by InsertableFields and UpdatableFields.
+	// Such a column is still read and written by ToMap and FromMap, unlike a Transient one.
+	ReadOnly bool
+	// Transient indicates the tag contains the "transient" option: the field is parsed like any other,
+	// with a resolved Column, and stays in Fields for introspection, but the persistence helpers (ToMap,
+	// FromMap, Columns, InsertableFields, UpdatableFields, ColumnNames, ExtractRows and ChangedColumns)
+	// skip it. Ignored fields, tagged "-", are skipped too but have no column at all.
+	Transient bool
+	// OmitEmpty indicates the tag contains the "omitempty" option; ToMap then omits the field
+	// when IsEmptyValue reports its value as empty.
+	OmitEmpty bool
+	// Required indicates the tag contains the "required" (or "required=true") option (see CheckRequired).
+	Required bool
+	// Aliases are alternative source keys from repeated "alias=..." tag options, in declaration order
+	// (e.g. ["userId", "id"] for schema:"user_id,alias=userId,alias=id"). FromMap tries Column first,
+	// then each alias, and FieldByColumn falls back to them. Nil when there are none.
+	Aliases []string
+	// Order is the value of the "order=N" tag option used by FieldsOrdered, and HasOrder reports that
+	// the option is present, since order=0 is a valid position. Order is 0 without the option.
+	Order    int
+	HasOrder bool
+	// Default is the raw value of the "default=..." tag option (e.g. "8080"), converted by ApplyDefaults.
+	Default string
+	// Options holds the tag's "key=value" options (e.g. "default" -> "now()") and Flags its bare
+	// options (e.g. "omitempty"), as split by FieldTag.Classify. Each is nil when empty.
+	Options map[string]string
+	Flags   map[string]bool
 
 	// Tag-specific metadata: tag name -> metadata object
 	// A field can have multiple tags (e.g., schema + validate)
 	TagMetadata map[string]any // "schema" -> *SchemaMetadata, "body" -> *BodyMetadata, etc.
+
+	// Extra holds data attached by packages built on top of this one, such as compiled validation
+	// rules, keyed by a name of the caller's choosing. The schema package itself never writes to it.
+	// It is nil until SetExtra is first called.
+	Extra map[string]any
+
+	// immutable is copied from the owning StructMetadata, so SetExtra also rejects copies sharing Extra
+	immutable bool
+}
+
+// Validate checks every field and the struct as a whole, including duplicate explicit tag names,
+// duplicate resolved column names, duplicate index paths, index paths that do not resolve on Type or that
+// disagree with Index and IsNested (e.g. after editing a Clone), which would make GetValue and SetValue
+// panic, with WithColumnValidator column names
+// rejected by the validator and, with WithColumnUniquenessFold, columns differing only by case.
+// All problems are returned joined as *FieldError values,
+// each naming the offending field. It returns nil if the metadata is valid.
+func (m *StructMetadata) Validate() error {
+	var errs []error
+	for _, field := range m.Fields {
+		if err := validateField(field); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	errs = append(errs, validateColumns(m.Fields)...)
+	errs = append(errs, validateIndexPaths(m.Fields)...)
+	errs = append(errs, validateIndexBounds(m.Type, m.Fields)...)
+	if m.columnValidator != nil {
+		errs = append(errs, validateColumnNames(m.Fields, m.columnValidator)...)
+	}
+	if m.foldColumns {
+		errs = append(errs, validateFoldedColumns(m.Fields)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateColumns reports fields sharing an explicit tag name or a resolved column name.
+// Ignored fields have no column and are not checked.
+func validateColumns(fields []FieldMetadata) []error {
+	var errs []error
+	seen := make(map[string]FieldMetadata, len(fields))
+	for _, field := range fields {
+		if field.Column == "" {
+			continue
+		}
+
+		first, exists := seen[field.Column]
+		if !exists {
+			seen[field.Column] = field
+
+			continue
+		}
+
+		if first.Tag.Name != "" && field.Tag.Name != "" {
+			errs = append(errs, newFieldError(field.StructFieldName, ErrDuplicateTagName,
+				"duplicate tag name %q, already used by field %q", field.Tag.Name, first.StructFieldName))
+
+			continue
+		}
+
+		errs = append(errs, newFieldError(field.StructFieldName, ErrDuplicateColumn,
+			"duplicate column %q, already used by field %q", field.Column, first.StructFieldName))
+	}
+
+	return errs
+}
+
+// validateFoldedColumns reports fields whose resolved column differs only by case from the column
+// of an earlier field. Exact duplicates are left to validateColumns.
+func validateFoldedColumns(fields []FieldMetadata) []error {
+	var errs []error
+	seen := make(map[string]FieldMetadata, len(fields))
+	for _, field := range fields {
+		if field.Column == "" {
+			continue
+		}
+
+		key := strings.ToLower(field.Column)
+		first, exists := seen[key]
+		if !exists {
+			seen[key] = field
+
+			continue
+		}
+
+		if first.Column != field.Column {
+			errs = append(errs, newFieldError(field.StructFieldName, ErrDuplicateColumn,
+				"column %q differs only by case from column %q of field %q", field.Column, first.Column, first.StructFieldName))
+		}
+	}
+
+	return errs
+}
+
+// validateColumnNames reports fields whose resolved column is rejected by validate.
+// Fields without a column (ignored fields) are not checked.
+func validateColumnNames(fields []FieldMetadata, validate func(name string) error) []error {
+	var errs []error
+	for _, field := range fields {
+		if field.Column == "" {
+			continue
+		}
+
+		if err := validate(field.Column); err != nil {
+			errs = append(errs, newFieldError(field.StructFieldName, ErrInvalidColumn,
+				"invalid column %q: %v", field.Column, err))
+		}
+	}
+
+	return errs
+}
+
+// validateIndexPaths reports fields claiming the same index path, which would make them read and
+// write the same struct field. Fields without an IndexPath are compared by []int{Index}.
+func validateIndexPaths(fields []FieldMetadata) []error {
+	var errs []error
+	seen := make(map[string]string, len(fields))
+	for _, field := range fields {
+		path := field.IndexPath
+		if len(path) == 0 {
+			path = []int{field.Index}
+		}
+
+		key := indexPathKey(path)
+		first, exists := seen[key]
+		if !exists {
+			seen[key] = field.StructFieldName
+
+			continue
+		}
+
+		errs = append(errs, newFieldError(field.StructFieldName, ErrDuplicateIndexPath,
+			"index path %v already used by field %q", path, first))
+	}
+
+	return errs
+}
+
+// validateIndexBounds reports fields whose index path (or Index, without one) does not resolve on typ,
+// and fields whose Index or IsNested disagree with their index path: GetValue and SetValue use Index
+// directly for non-nested fields. Metadata without a struct type is only checked for the latter.
+func validateIndexBounds(typ reflect.Type, fields []FieldMetadata) []error {
+	var errs []error
+	for _, field := range fields {
+		path := field.IndexPath
+		if len(path) == 0 {
+			path = []int{field.Index}
+		} else {
+			if last := path[len(path)-1]; field.Index != last {
+				errs = append(errs, newFieldError(field.StructFieldName, ErrInvalidIndexPath,
+					"index %d does not match index path %v", field.Index, path))
+			}
+			if nested := len(path) > 1; field.IsNested != nested {
+				errs = append(errs, newFieldError(field.StructFieldName, ErrInvalidIndexPath,
+					"IsNested is %t but index path %v has %d elements", field.IsNested, path, len(path)))
+			}
+		}
+
+		if typ == nil || typ.Kind() != reflect.Struct {
+			continue
+		}
+
+		if err := resolveIndexPath(typ, path); err != nil {
+			errs = append(errs, newFieldError(field.StructFieldName, ErrInvalidIndexPath,
+				"index path %v does not resolve on %v: %v", path, typ, err))
+		}
+	}
+
+	return errs
+}
+
+// resolveIndexPath follows path through typ, dereferencing embedded pointers as reflect.Type.FieldByIndex
+// does, and reports the first step that does not resolve. Any panic from reflect is recovered and
+// returned as an error, so corrupted metadata cannot crash Validate.
+func resolveIndexPath(typ reflect.Type, path []int) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	for i, idx := range path {
+		if i > 0 {
+			typ = derefType(typ)
+		}
+		if typ.Kind() != reflect.Struct {
+			return fmt.Errorf("%v at position %d is not a struct", typ, i)
+		}
+		if idx < 0 || idx >= typ.NumField() {
+			return fmt.Errorf("index %d at position %d out of range for %v with %d fields", idx, i, typ, typ.NumField())
+		}
+		typ = typ.Field(idx).Type
+	}
+
+	return nil
+}
+
+// indexPathKey encodes an index path as a comparable map key.
+func indexPathKey(path []int) string {
+	var sb strings.Builder
+	for i, index := range path {
+		if i > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(strconv.Itoa(index))
+	}
+
+	return sb.String()
 }
 
 // validateField validates a single FieldMetadata and returns an error if invalid.
+// Each failed rule is reported as a *FieldError.
 func validateField(field FieldMetadata) error {
 	var errs []error
+	name := field.StructFieldName
 
-	if field.StructFieldName == "" {
-		errs = append(errs, fmt.Errorf("structFieldName cannot be empty"))
+	if name == "" {
+		errs = append(errs, newFieldError(name, ErrEmptyName, "structFieldName cannot be empty"))
 	}
 
 	if field.Type == nil {
-		errs = append(errs, fmt.Errorf("type cannot be nil"))
+		errs = append(errs, newFieldError(name, ErrNilType, "type cannot be nil"))
 	}
 
 	if field.Index < 0 {
-		errs = append(errs, fmt.Errorf("index must be non-negative, got %d", field.Index))
+		errs = append(errs, newFieldError(name, ErrNegativeIndex, "index must be non-negative, got %d", field.Index))
 	}
 
 	if len(errs) > 0 {
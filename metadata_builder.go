@@ -1,84 +1,688 @@
 package schema
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"errors"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// defaultScalarTypes are struct types treated as leaf values rather than descended into.
+var defaultScalarTypes = []reflect.Type{
+	timeType,
+}
+
+var (
+	scannerType = reflect.TypeFor[sql.Scanner]()
+	valuerType  = reflect.TypeFor[driver.Valuer]()
+
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
 )
 
 // metadataBuilder orchestrates parsing using registered parsers.
 type metadataBuilder struct {
-	registry *TagParserRegistry
+	registry        *TagParserRegistry
+	tagKey          string
+	fallbackKeys    []string
+	naming          NamingStrategy
+	nested          bool
+	prefixed        bool
+	caseInsensitive bool
+	unexported      bool
+	scalarTypes     map[reflect.Type]bool
+	sqlTypes        SQLTypeMapper
+	filter          func(field reflect.StructField) bool
+	strictTags      bool
+	columnValidator func(name string) error
+	knownOptions    map[string]bool
+	maxEmbedDepth   int
+	typeDecoders    map[reflect.Type]typeConverter
+	typeEncoders    map[reflect.Type]typeConverter
+	overrides       map[reflect.Type]map[string]string
+	foldColumns     bool
+	requireTag      bool
+	immutable       bool
+	conflictPolicy  ConflictPolicy
+	warnings        bool
+	fieldHooks      []func(field *FieldMetadata) error
 }
 
 // newMetadataBuilder creates a new metadata builder.
-func newMetadataBuilder(registry *TagParserRegistry) *metadataBuilder {
-	return &metadataBuilder{
-		registry: registry,
+func newMetadataBuilder(registry *TagParserRegistry, opts ...MetadataOption) *metadataBuilder {
+	b := &metadataBuilder{
+		registry:      registry,
+		tagKey:        defaultSchemaTag,
+		naming:        SnakeCaseNaming{},
+		scalarTypes:   make(map[reflect.Type]bool, len(defaultScalarTypes)),
+		knownOptions:  make(map[string]bool, len(builtinTagOptions)),
+		maxEmbedDepth: -1,
+	}
+	for _, typ := range defaultScalarTypes {
+		b.scalarTypes[typ] = true
+	}
+	for _, option := range builtinTagOptions {
+		b.knownOptions[option] = true
 	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
 }
 
 // BuildStructMetadata parses the struct type and returns its metadata.
 func (b *metadataBuilder) buildStructMetadata(typ reflect.Type) (*StructMetadata, error) {
+	return b.build(typ, make(map[reflect.Type]*StructMetadata))
+}
+
+// build parses typ. inProgress maps the types currently being built to their pre-allocated metadata,
+// so a recursive reference (e.g. Next *Node) resolves to the same, possibly partial, instance.
+func (b *metadataBuilder) build(typ reflect.Type, inProgress map[reflect.Type]*StructMetadata) (*StructMetadata, error) {
+	structMeta := &StructMetadata{Type: typ}
+	inProgress[typ] = structMeta
+	defer delete(inProgress, typ)
+
+	fields, errs := b.collectFields(typ, nil, "", map[reflect.Type]bool{typ: true}, inProgress)
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("parsing errors: %w", errors.Join(errs...))
+	}
+
+	fields, err := resolveColumnConflicts(fields, b.conflictPolicy)
+	if err != nil {
+		return nil, err
+	}
+	fields = resolvePromotedFields(typ, fields)
+	if overrides := b.overrides[typ]; len(overrides) > 0 {
+		if err := applyColumnOverrides(fields, overrides); err != nil {
+			return nil, err
+		}
+	}
+	if b.filter != nil {
+		fields = slices.DeleteFunc(fields, func(field FieldMetadata) bool {
+			return !b.filter(field.StructField)
+		})
+	}
+
+	for i := range fields {
+		for _, hook := range b.fieldHooks {
+			if err := hook(&fields[i]); err != nil {
+				return nil, fmt.Errorf("field hook: field %s: %w", fields[i].StructFieldName, err)
+			}
+		}
+	}
+
+	built, err := NewStructMetadata(typ, fields)
+	if err != nil {
+		return nil, err
+	}
+	*structMeta = *built
+	structMeta.sqlTypeMapper = b.sqlTypes
+	structMeta.columnValidator = b.columnValidator
+	structMeta.foldColumns = b.foldColumns
+	if b.warnings {
+		structMeta.warnings = b.collectWarnings(structMeta.Fields)
+	}
+	if b.immutable {
+		structMeta.immutable = true
+		for i := range structMeta.Fields {
+			structMeta.Fields[i].immutable = true
+		}
+	}
+
+	if b.caseInsensitive {
+		if err := structMeta.indexFoldedColumns(); err != nil {
+			return nil, fmt.Errorf("case-insensitive columns: %w", err)
+		}
+	}
+
+	return structMeta, nil
+}
+
+// collectFields walks the fields of typ, flattening anonymous struct fields, and named struct fields
+// with the inline option, into their promoted fields. parentIndex is the index path of typ within the
+// root struct, columnPrefix is prepended to the columns of its fields (see WithEmbeddedPrefix), and
+// visited guards against embedding cycles.
+func (b *metadataBuilder) collectFields(typ reflect.Type, parentIndex []int, columnPrefix string, visited map[reflect.Type]bool, inProgress map[reflect.Type]*StructMetadata) ([]FieldMetadata, []error) {
 	var fields []FieldMetadata
 	var errs []error
+	// declared records the names of fields declared directly on typ, inlinedFrom the inline field
+	// providing each promoted name. Collisions between flattened structs are left to Go's shadowing
+	// rules and resolveColumnConflicts.
+	declared := make(map[string]bool)
+	inlinedFrom := make(map[string]string)
 
 	for i := 0; i < typ.NumField(); i++ {
 		field := typ.Field(i)
+		indexPath := append(slices.Clone(parentIndex), i)
+
+		// Flatten embedded structs (including unexported ones, like encoding/json) and inline fields
+		if embeddedType, ok := b.flattenableType(field, len(parentIndex), visited); ok {
+			if b.strictTags {
+				tag, _ := b.parseTag(field)
+				errs = append(errs, b.checkTagOptions(field.Name, tag)...)
+			}
+
+			visited[embeddedType] = true
+			promoted, promotedErrs := b.collectFields(embeddedType, indexPath, columnPrefix+b.embeddedPrefix(field), visited, inProgress)
+			delete(visited, embeddedType)
+
+			if !field.Anonymous {
+				for _, p := range promoted {
+					if _, seen := inlinedFrom[p.StructFieldName]; !seen {
+						inlinedFrom[p.StructFieldName] = field.Name
+					}
+				}
+			}
+
+			fields = append(fields, promoted...)
+			errs = append(errs, promotedErrs...)
 
-		// Check if unexported (skip)
-		if !field.IsExported() {
 			continue
 		}
 
-		// Create FieldMetadata with basic info
-		fieldMetadata := FieldMetadata{
-			StructFieldName: field.Name,
-			Index:           i,
-			Type:            field.Type,
-			Embedded:        field.Anonymous,
-			TagMetadata:     make(map[string]any),
+		// Check if unexported (skip unless requested)
+		if !field.IsExported() && !b.unexported {
+			continue
 		}
 
-		// Iterate through registered parsers (by tag name) to check if field has the tag
-		// Go doesn't provide a way to enumerate struct tag keys, so we check registered tags
-		allParsers := b.registry.All()
+		fieldMetadata, fieldErrs := b.buildFieldMetadata(field, indexPath, columnPrefix, inProgress)
+		if len(fieldErrs) > 0 {
+			errs = append(errs, fieldErrs...)
 
-		for tagName, parser := range allParsers {
-			// Check if field has the tag
-			tagValue, ok := field.Tag.Lookup(tagName)
-			if !ok {
-				// Field doesn't have this tag, try to apply default for this tag type
-				if defaultFunc := b.registry.GetDefault(tagName); defaultFunc != nil {
-					fieldMetadata.TagMetadata[tagName] = defaultFunc(field, i)
-				}
+			continue
+		}
 
-				continue
+		// Validate field has at least one TagMetadata entry
+		if len(fieldMetadata.TagMetadata) == 0 {
+			// Skip fields with no metadata
+			continue
+		}
+
+		declared[fieldMetadata.StructFieldName] = true
+		fields = append(fields, fieldMetadata)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(inlinedFrom)) {
+		if declared[name] {
+			errs = append(errs, newFieldError(name, ErrFieldConflict,
+				"field inlined from %q conflicts with another field of %v", inlinedFrom[name], typ))
+		}
+	}
+
+	return fields, errs
+}
+
+// flattenableType returns the struct type of an anonymous field, or of an exported named field with the
+// inline (or squash) tag option, whose fields should be promoted. Embedded interfaces (e.g. io.Reader)
+// are never flattened: they have no fields, only a method set, and are kept as a single field like any
+// other interface-typed field. depth is the number of embedding levels above field; at WithMaxEmbedDepth
+// levels embedded and inline structs are kept as single fields too.
+func (b *metadataBuilder) flattenableType(field reflect.StructField, depth int, visited map[reflect.Type]bool) (reflect.Type, bool) {
+	if field.Type.Kind() == reflect.Interface {
+		return nil, false
+	}
+
+	if b.maxEmbedDepth >= 0 && depth >= b.maxEmbedDepth {
+		return nil, false
+	}
+
+	tag, ignored := b.parseTag(field)
+	if ignored {
+		return nil, false
+	}
+
+	if !field.Anonymous && (!field.IsExported() || !(tag.HasOption(tagOptionInline) || tag.HasOption(tagOptionSquash))) {
+		return nil, false
+	}
+
+	typ := field.Type
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct || visited[typ] || b.isScalar(typ) {
+		return nil, false
+	}
+
+	return typ, true
+}
+
+// buildFieldMetadata creates the FieldMetadata for a single field located at indexPath.
+// columnPrefix is prepended to the resolved column name.
+func (b *metadataBuilder) buildFieldMetadata(field reflect.StructField, indexPath []int, columnPrefix string, inProgress map[reflect.Type]*StructMetadata) (FieldMetadata, []error) {
+	var errs []error
+	index := indexPath[len(indexPath)-1]
+	baseType := derefType(field.Type)
+
+	// Create FieldMetadata with basic info
+	fieldMetadata := FieldMetadata{
+		StructFieldName:   field.Name,
+		Index:             index,
+		IndexPath:         indexPath,
+		Type:              field.Type,
+		StructField:       field,
+		Exported:          field.IsExported(),
+		IsPointer:         field.Type.Kind() == reflect.Pointer,
+		IsSlice:           baseType.Kind() == reflect.Slice,
+		IsArray:           baseType.Kind() == reflect.Array,
+		IsMap:             baseType.Kind() == reflect.Map,
+		IsStruct:          baseType.Kind() == reflect.Struct,
+		IsInterface:       baseType.Kind() == reflect.Interface,
+		IsScalar:          b.isScalar(field.Type),
+		IsScanner:         implements(field.Type, scannerType),
+		IsValuer:          implements(field.Type, valuerType),
+		IsTextMarshaler:   implements(field.Type, textMarshalerType),
+		IsTextUnmarshaler: implements(field.Type, textUnmarshalerType),
+		ElemType:          baseType,
+		Embedded:          field.Anonymous,
+		TagMetadata:       make(map[string]any),
+	}
+	if fieldMetadata.IsSlice || fieldMetadata.IsArray {
+		fieldMetadata.ElemType = baseType.Elem()
+	}
+	if fieldMetadata.IsArray {
+		fieldMetadata.ArrayLen = baseType.Len()
+	}
+	if fieldMetadata.IsMap {
+		fieldMetadata.KeyType = baseType.Key()
+		fieldMetadata.ValueType = baseType.Elem()
+	}
+	var nameKey string
+	fieldMetadata.Tag, nameKey, fieldMetadata.Ignored = b.parseTagSource(field)
+	if column := b.resolveColumn(fieldMetadata); column != "" {
+		fieldMetadata.Column = column
+		if columnPrefix != "" {
+			fieldMetadata.Column = intern(columnPrefix + column)
+		}
+		fieldMetadata.SourceTag = SourceNaming
+		if nameKey != "" {
+			fieldMetadata.SourceTag = nameKey
+		}
+	}
+	fieldMetadata.IsPrimaryKey = fieldMetadata.Tag.HasOption(tagOptionPrimaryKey) ||
+		fieldMetadata.Tag.HasOption(tagOptionPrimaryKeyShort)
+	fieldMetadata.ReadOnly = fieldMetadata.Tag.HasOption(tagOptionReadOnly) ||
+		fieldMetadata.Tag.HasOption(tagOptionReadOnlyShort)
+	fieldMetadata.Default, _ = fieldMetadata.Tag.OptionValue(tagOptionDefault)
+	aliases := slices.DeleteFunc(fieldMetadata.Tag.OptionValues(tagOptionAlias), func(alias string) bool {
+		return alias == ""
+	})
+	if len(aliases) > 0 && !fieldMetadata.Ignored {
+		fieldMetadata.Aliases = aliases
+	}
+	if order, ok := fieldMetadata.Tag.OptionValue(tagOptionOrder); ok {
+		n, err := strconv.Atoi(order)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("field %s: invalid order %q: expected an integer", field.Name, order))
+		}
+		fieldMetadata.Order, fieldMetadata.HasOrder = n, err == nil
+	}
+	fieldMetadata.Required = fieldMetadata.Tag.BoolOption(tagOptionRequired)
+	fieldMetadata.OmitEmpty = fieldMetadata.Tag.HasOption(tagOptionOmitEmpty)
+	fieldMetadata.Transient = fieldMetadata.Tag.HasOption(tagOptionTransient)
+	fieldMetadata.Options, fieldMetadata.Flags = fieldMetadata.Tag.Classify()
+	if b.strictTags {
+		errs = append(errs, b.checkTagOptions(field.Name, fieldMetadata.Tag)...)
+	}
+	if b.requireTag && field.IsExported() && !field.Anonymous && !fieldMetadata.Ignored {
+		if _, ok := field.Tag.Lookup(b.tagKey); !ok {
+			errs = append(errs, newFieldError(field.Name, ErrMissingTag, "missing %q tag", b.tagKey))
+		}
+	}
+
+	// Iterate through registered parsers (by tag name) to check if field has the tag
+	// Go doesn't provide a way to enumerate struct tag keys, so we check registered tags
+	allParsers := b.registry.All()
+
+	for tagName, parser := range allParsers {
+		// Check if field has the tag
+		tagValue, ok := field.Tag.Lookup(tagName)
+		if !ok {
+			// Field doesn't have this tag, try to apply default for this tag type
+			if defaultFunc := b.registry.GetDefault(tagName); defaultFunc != nil {
+				fieldMetadata.TagMetadata[tagName] = defaultFunc(field, index)
 			}
 
-			// Field has the tag, parse it normally
-			metadata, err := parser(field, i, tagValue)
-			if err != nil || metadata == nil {
-				errs = append(errs, fmt.Errorf("field %s: failed to parse tag %q: %w", field.Name, tagName, err))
+			continue
+		}
+
+		// Field has the tag, parse it normally
+		metadata, err := parser(field, index, tagValue)
+		if err != nil || metadata == nil {
+			errs = append(errs, fmt.Errorf("field %s: failed to parse tag %q: %w", field.Name, tagName, err))
+
+			continue
+		}
+
+		// Store in FieldMetadata.TagMetadata[tagName] = metadata
+		fieldMetadata.TagMetadata[tagName] = metadata
+	}
 
+	if b.nested {
+		elemMetadata, err := b.buildElemMetadata(fieldMetadata.ElemType, inProgress)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("field %s: %w", field.Name, err))
+		}
+		fieldMetadata.ElemMetadata = elemMetadata
+	}
+
+	return fieldMetadata, errs
+}
+
+// buildElemMetadata parses the struct type behind elemType (unwrapping pointers).
+// It returns nil for non-struct and scalar types. A type that is already being built resolves
+// to its in-progress metadata instead of being parsed again, which breaks cycles.
+func (b *metadataBuilder) buildElemMetadata(elemType reflect.Type, inProgress map[reflect.Type]*StructMetadata) (*StructMetadata, error) {
+	structType := derefType(elemType)
+	if structType.Kind() != reflect.Struct || b.isScalar(structType) {
+		return nil, nil //nolint:nilnil // No nested metadata is a valid result
+	}
+
+	if existing, ok := inProgress[structType]; ok {
+		return existing, nil
+	}
+
+	return b.build(structType, inProgress)
+}
+
+// parseTag parses the field tag under the primary tag key, then the fallback keys (see WithTagFallback).
+func (b *metadataBuilder) parseTag(field reflect.StructField) (FieldTag, bool) {
+	tag, _, ignored := b.parseTagSource(field)
+
+	return tag, ignored
+}
+
+// parseTagSource is parseTag that also returns the tag key that supplied the tag name, or "" if no
+// tag names the field.
+func (b *metadataBuilder) parseTagSource(field reflect.StructField) (FieldTag, string, bool) {
+	tag, ignored := ParseFieldTag(field, b.tagKey)
+	nameKey := ""
+	if tag.Name != "" {
+		nameKey = b.tagKey
+	}
+	if ignored || len(b.fallbackKeys) == 0 {
+		return tag, nameKey, ignored
+	}
+
+	present := field.Tag.Get(b.tagKey) != ""
+	for _, key := range b.fallbackKeys {
+		if field.Tag.Get(key) == "" {
+			continue
+		}
+
+		// A "-" only ignores the field when no earlier key supplied a tag, so db:"password_hash" json:"-"
+		// keeps the field under the db name.
+		fallback, fallbackIgnored := ParseFieldTag(field, key)
+		if fallbackIgnored {
+			if present {
 				continue
 			}
 
-			// Store in FieldMetadata.TagMetadata[tagName] = metadata
-			fieldMetadata.TagMetadata[tagName] = metadata
+			return FieldTag{}, "", true
 		}
+		if tag.Name == "" && fallback.Name != "" {
+			tag.Name = fallback.Name
+			nameKey = key
+		}
+		if !present {
+			tag.Options = fallback.Options
+			present = true
+		}
+	}
 
-		// Validate field has at least one TagMetadata entry
-		if len(fieldMetadata.TagMetadata) == 0 {
-			// Skip fields with no metadata
+	return tag, nameKey, false
+}
+
+// embeddedPrefix returns the column prefix for the fields promoted from the embedded field:
+// the "prefix=..." tag option if present, otherwise, with WithEmbeddedPrefix, the field name
+// converted by the naming strategy followed by "_" (Address -> "address_").
+func (b *metadataBuilder) embeddedPrefix(field reflect.StructField) string {
+	tag, _ := b.parseTag(field)
+	if prefix, ok := tag.OptionValue(tagOptionPrefix); ok {
+		return prefix
+	}
+
+	if !b.prefixed {
+		return ""
+	}
+
+	return intern(b.columnName(field.Name) + "_")
+}
+
+// checkTagOptions returns a *FieldError for each option key of tag that is not known (see WithStrictTags).
+func (b *metadataBuilder) checkTagOptions(fieldName string, tag FieldTag) []error {
+	var errs []error
+	for _, opt := range tag.Options {
+		key, _, found := strings.Cut(opt, "=")
+		if !found || key == "" {
+			key = opt
+		}
+
+		if !b.knownOptions[key] {
+			errs = append(errs, newFieldError(fieldName, ErrUnknownOption, "unknown tag option %q", key))
+		}
+	}
+
+	return errs
+}
+
+// collectWarnings returns the warnings for fields (see WithCollectWarnings): a missing tag unless
+// WithRequireTag already makes it an error, and unknown tag options unless WithStrictTags does.
+// Each warning is the message of the *FieldError the strict option would report.
+func (b *metadataBuilder) collectWarnings(fields []FieldMetadata) []string {
+	var warnings []string
+	for _, field := range fields {
+		if field.Ignored {
 			continue
 		}
+		if !b.requireTag && field.Exported && !field.Embedded {
+			if _, ok := field.StructField.Tag.Lookup(b.tagKey); !ok {
+				warnings = append(warnings, newFieldError(field.StructFieldName, ErrMissingTag, "missing %q tag", b.tagKey).Error())
+			}
+		}
+		if !b.strictTags {
+			for _, err := range b.checkTagOptions(field.StructFieldName, field.Tag) {
+				warnings = append(warnings, err.Error())
+			}
+		}
+	}
 
-		fields = append(fields, fieldMetadata)
+	return warnings
+}
+
+// isScalar reports whether typ, or the type behind its pointers, is a registered scalar type
+// or implements sql.Scanner or driver.Valuer.
+func (b *metadataBuilder) isScalar(typ reflect.Type) bool {
+	return b.scalarTypes[derefType(typ)] || implements(typ, scannerType) || implements(typ, valuerType)
+}
+
+// implements reports whether the non-pointer type behind typ, or a pointer to it, implements iface.
+// Interface types are never reported, since only their dynamic values can implement anything.
+func implements(typ, iface reflect.Type) bool {
+	base := derefType(typ)
+	if base.Kind() == reflect.Interface {
+		return false
+	}
+
+	return base.Implements(iface) || reflect.PointerTo(base).Implements(iface)
+}
+
+// applyColumnOverrides replaces the columns of fields named in overrides, the WithColumnOverrides
+// entries of the type being built. Overrides naming no field are an error.
+func applyColumnOverrides(fields []FieldMetadata, overrides map[string]string) error {
+	matched := make(map[string]bool, len(overrides))
+	for i := range fields {
+		column, ok := overrides[fields[i].StructFieldName]
+		if !ok {
+			continue
+		}
+		matched[fields[i].StructFieldName] = true
+		if !fields[i].Ignored {
+			fields[i].Column = column
+			fields[i].SourceTag = SourceOverride
+		}
+	}
+
+	if len(matched) == len(overrides) {
+		return nil
+	}
+
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(overrides)) {
+		if !matched[name] {
+			errs = append(errs, fmt.Errorf("column override for unknown field %q", name))
+		}
+	}
+
+	return fmt.Errorf("column overrides: %w", errors.Join(errs...))
+}
+
+// resolveColumn returns the tag name if present, otherwise the name derived by the naming strategy,
+// interned (see intern). Ignored fields have no column.
+func (b *metadataBuilder) resolveColumn(field FieldMetadata) string {
+	if field.Ignored {
+		return ""
+	}
+
+	if field.Tag.Name != "" {
+		return intern(field.Tag.Name)
+	}
+
+	return b.columnName(field.StructFieldName)
+}
+
+// resolveColumnConflicts applies policy to fields promoted from different embedded structs at the same
+// depth that resolve to the same column, such as Name in two embedded structs. Fields shadowed by a
+// shallower field of the same name are not considered, and neither are columns of the root struct itself,
+// which Validate reports. With ConflictError every field after the first of a conflicting group is
+// reported as a *FieldError with rule ErrDuplicateColumn; otherwise all but the first (ConflictKeepFirst)
+// or the last (ConflictKeepLast) field of the group are dropped.
+func resolveColumnConflicts(fields []FieldMetadata, policy ConflictPolicy) ([]FieldMetadata, error) {
+	nameDepth := make(map[string]int, len(fields))
+	for _, field := range fields {
+		if depth, seen := nameDepth[field.StructFieldName]; !seen || len(field.IndexPath) < depth {
+			nameDepth[field.StructFieldName] = len(field.IndexPath)
+		}
+	}
+
+	// Group the unshadowed fields at the shallowest depth of each column
+	groups := make(map[string][]int)
+	columnDepth := make(map[string]int)
+	for i, field := range fields {
+		depth := len(field.IndexPath)
+		if field.Column == "" || depth != nameDepth[field.StructFieldName] {
+			continue
+		}
+		current, seen := columnDepth[field.Column]
+		switch {
+		case !seen || depth < current:
+			columnDepth[field.Column] = depth
+			groups[field.Column] = []int{i}
+		case depth == current:
+			groups[field.Column] = append(groups[field.Column], i)
+		}
+	}
+
+	drop := make(map[int]bool)
+	var errs []error
+	for _, column := range slices.Sorted(maps.Keys(groups)) {
+		group := groups[column]
+		if len(group) < 2 || columnDepth[column] < 2 {
+			continue
+		}
+
+		switch policy {
+		case ConflictKeepFirst:
+			group = group[1:]
+		case ConflictKeepLast:
+			group = group[:len(group)-1]
+		default:
+			first := fields[group[0]]
+			for _, i := range group[1:] {
+				errs = append(errs, newFieldError(fields[i].StructFieldName, ErrDuplicateColumn,
+					"column %q at index %v conflicts with field %q at index %v (see WithConflictPolicy)",
+					column, fields[i].IndexPath, first.StructFieldName, first.IndexPath))
+			}
+
+			continue
+		}
+		for _, i := range group {
+			drop[i] = true
+		}
 	}
 
 	if len(errs) > 0 {
-		return nil, fmt.Errorf("parsing errors: %w", fmt.Errorf("%v", errs))
+		return nil, fmt.Errorf("column conflicts: %w", errors.Join(errs...))
+	}
+	if len(drop) == 0 {
+		return fields, nil
+	}
+
+	result := make([]FieldMetadata, 0, len(fields)-len(drop))
+	for i, field := range fields {
+		if !drop[i] {
+			result = append(result, field)
+		}
+	}
+
+	return result, nil
+}
+
+// resolvePromotedFields applies Go's shadowing rules to fields collected from embedded structs of typ.
+// For each field name, the shallowest field wins; if several fields share the shallowest depth,
+// the name is ambiguous and all of them are dropped. Fields reached through an inline field are never
+// ambiguous, since Go does not promote them: sibling inline fields such as Home and Work Address both
+// keep their City, and any column collision between them is left to resolveColumnConflicts.
+// Declaration order is preserved.
+func resolvePromotedFields(typ reflect.Type, fields []FieldMetadata) []FieldMetadata {
+	minDepth := make(map[string]int, len(fields))
+	countAtMin := make(map[string]int, len(fields))
+	inlined := make([]bool, len(fields))
+	for i, field := range fields {
+		inlined[i] = throughInlineField(typ, field.IndexPath)
+		depth := len(field.IndexPath)
+		current, seen := minDepth[field.StructFieldName]
+		switch {
+		case !seen || depth < current:
+			minDepth[field.StructFieldName] = depth
+			countAtMin[field.StructFieldName] = 0
+		case depth > current:
+			continue
+		}
+		if !inlined[i] {
+			countAtMin[field.StructFieldName]++
+		}
+	}
+
+	result := make([]FieldMetadata, 0, len(fields))
+	for i, field := range fields {
+		if len(field.IndexPath) == minDepth[field.StructFieldName] && (inlined[i] || countAtMin[field.StructFieldName] == 1) {
+			result = append(result, field)
+		}
+	}
+
+	return result
+}
+
+// throughInlineField reports whether indexPath, within typ, passes through a named struct field, which
+// collectFields only flattens for the inline option.
+func throughInlineField(typ reflect.Type, indexPath []int) bool {
+	for _, i := range indexPath[:len(indexPath)-1] {
+		field := typ.Field(i)
+		if !field.Anonymous {
+			return true
+		}
+		typ = derefType(field.Type)
 	}
 
-	return NewStructMetadata(typ, fields)
+	return false
 }
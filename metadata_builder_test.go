@@ -1,8 +1,17 @@
 package schema
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -182,3 +191,1600 @@ func TestMetadataBuilder_BuildStructMetadata_HandlesParsingErrors(t *testing.T)
 	assert.Contains(t, err.Error(), "Valid")
 	assert.Contains(t, err.Error(), "Invalid")
 }
+
+func TestMetadataBuilder_BuildStructMetadata_ParsesFieldTag(t *testing.T) {
+	type testStruct struct {
+		Name    string `schema:"column_name,omitempty"`
+		Plain   string
+		Skipped string `schema:"-"`
+	}
+
+	builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+	result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+	require.NoError(t, err)
+
+	nameField, ok := result.Field("Name")
+	require.True(t, ok)
+	assert.Equal(t, "column_name", nameField.Tag.Name)
+	assert.Equal(t, []string{"omitempty"}, nameField.Tag.Options)
+	assert.False(t, nameField.Ignored)
+
+	plainField, ok := result.Field("Plain")
+	require.True(t, ok)
+	assert.Empty(t, plainField.Tag.Name)
+	assert.False(t, plainField.Ignored)
+
+	skippedField, ok := result.Field("Skipped")
+	require.True(t, ok)
+	assert.True(t, skippedField.Ignored)
+}
+
+func TestMetadataBuilder_BuildStructMetadata_OptionsAndFlags(t *testing.T) {
+	type testStruct struct {
+		CreatedAt string `schema:"created_at,default=now(),omitempty,pk"`
+		Name      string `schema:"name"`
+	}
+
+	builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+	result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+	require.NoError(t, err)
+
+	createdAt := result.MustFieldByName("CreatedAt")
+	assert.Equal(t, "created_at", createdAt.Column)
+	assert.Equal(t, map[string]string{"default": "now()"}, createdAt.Options)
+	assert.Equal(t, map[string]bool{"omitempty": true, "pk": true}, createdAt.Flags)
+	assert.True(t, createdAt.IsPrimaryKey)
+	assert.Equal(t, "now()", createdAt.Default)
+
+	assert.True(t, createdAt.OmitEmpty)
+
+	name := result.MustFieldByName("Name")
+	assert.Nil(t, name.Options)
+	assert.Nil(t, name.Flags)
+	assert.False(t, name.OmitEmpty)
+}
+
+func TestMetadataBuilder_BuildStructMetadata_StrictTags(t *testing.T) {
+	type embeddedBase struct {
+		CreatedAt string `schema:"created_at"`
+	}
+	type testStruct struct {
+		ID           int    `schema:"id,primarykey"`
+		Name         string `schema:"name,omitempty,default=x,size=64"`
+		Email        string `schema:"email,index"`
+		embeddedBase `schema:",prefix=base_,flatten"`
+	}
+
+	t.Run("permissive by default", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bool{"primarykey": true}, result.MustFieldByName("ID").Flags)
+		assert.False(t, result.MustFieldByName("ID").IsPrimaryKey)
+	})
+
+	t.Run("strict reports unknown options", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithStrictTags())
+
+		_, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.Error(t, err)
+		assert.Equal(t, []ValidationRule{ErrUnknownOption, ErrUnknownOption, ErrUnknownOption, ErrUnknownOption}, fieldErrorRules(err))
+		assert.Contains(t, err.Error(), `field "ID": unknown tag option "primarykey"`)
+		assert.Contains(t, err.Error(), `field "Name": unknown tag option "size"`)
+		assert.Contains(t, err.Error(), `field "Email": unknown tag option "index"`)
+		assert.Contains(t, err.Error(), `field "embeddedBase": unknown tag option "flatten"`)
+
+		var fieldErr *FieldError
+		require.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, ErrUnknownOption, fieldErr.Rule)
+	})
+
+	t.Run("strict with known options", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(),
+			WithStrictTags(), WithKnownOptions("primarykey", "size", "index", "flatten", ""))
+
+		_, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+	})
+
+	t.Run("strict accepts parameter options", func(t *testing.T) {
+		type request struct {
+			ID    int      `schema:"id,location=path,required"`
+			IDs   []string `schema:"ids,location=query,style=form,explode=true"`
+			Token string   `schema:"X-Token,location=header,style=simple,explode=false"`
+		}
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithStrictTags())
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(request{}))
+
+		require.NoError(t, err)
+		assert.Len(t, result.Fields, 3)
+	})
+}
+
+func TestMetadataBuilder_BuildStructMetadata_WithRequireTag(t *testing.T) {
+	type embeddedBase struct {
+		CreatedAt string `schema:"created_at"`
+		UpdatedAt string
+	}
+	type testStruct struct {
+		ID       int    `schema:"id,pk"`
+		Name     string `schema:",omitempty"`
+		Email    string
+		Nickname string `json:"nickname"`
+		Secret   string `schema:"-"`
+		Skipped  string `json:"-"`
+		internal string
+		embeddedBase
+		time.Time
+	}
+
+	t.Run("reports untagged fields", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithRequireTag(), WithTagFallback("json"))
+
+		_, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.Error(t, err)
+		assert.Equal(t, []ValidationRule{ErrMissingTag, ErrMissingTag, ErrMissingTag}, fieldErrorRules(err))
+		assert.Contains(t, err.Error(), `field "Email": missing "schema" tag`)
+		assert.Contains(t, err.Error(), `field "Nickname": missing "schema" tag`, "fallback keys do not count")
+		assert.Contains(t, err.Error(), `field "UpdatedAt": missing "schema" tag`, "promoted fields are checked")
+		assert.NotContains(t, err.Error(), `"Name"`, "a tag without a name satisfies the check")
+	})
+
+	t.Run("uses the configured tag key", func(t *testing.T) {
+		type dbStruct struct {
+			ID   int    `db:"id"`
+			Name string `schema:"name"`
+		}
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithRequireTag(), WithTagKey("db"))
+
+		_, err := builder.buildStructMetadata(reflect.TypeOf(dbStruct{}))
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `field "Name": missing "db" tag`)
+		assert.NotContains(t, err.Error(), `"ID"`)
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+		_, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+	})
+}
+
+func TestMetadataBuilder_BuildStructMetadata_WithTagKey(t *testing.T) {
+	type testStruct struct {
+		ID   int    `db:"user_id" schema:"id"`
+		Name string `db:"-"`
+	}
+
+	builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithTagKey("db"))
+
+	result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+	require.NoError(t, err)
+
+	idField, ok := result.Field("ID")
+	require.True(t, ok)
+	assert.Equal(t, "user_id", idField.Tag.Name)
+
+	nameField, ok := result.Field("Name")
+	require.True(t, ok)
+	assert.True(t, nameField.Ignored)
+}
+
+func TestMetadataBuilder_BuildStructMetadata_WithTagFallback(t *testing.T) {
+	type testStruct struct {
+		ID        int    `schema:"id" json:"identifier"`
+		UserName  string `json:"user_name,omitempty"`
+		Email     string `schema:",required" json:"email_address"`
+		Password  string `json:"-"`
+		Token     string `schema:"-" json:"token"`
+		CreatedAt string
+	}
+
+	builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithTagFallback("json"))
+
+	result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+	require.NoError(t, err)
+
+	tests := []struct {
+		field   string
+		column  string
+		options []string
+		ignored bool
+	}{
+		{field: "ID", column: "id"},
+		{field: "UserName", column: "user_name", options: []string{"omitempty"}},
+		{field: "Email", column: "email_address", options: []string{"required"}},
+		{field: "Password", ignored: true},
+		{field: "Token", ignored: true},
+		{field: "CreatedAt", column: "created_at"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field := result.MustFieldByName(tt.field)
+			assert.Equal(t, tt.column, field.Column)
+			assert.Equal(t, tt.options, field.Tag.Options)
+			assert.Equal(t, tt.ignored, field.Ignored)
+		})
+	}
+
+	t.Run("fallback dash does not drop a named field", func(t *testing.T) {
+		type account struct {
+			ID           int    `db:"id"`
+			PasswordHash string `db:"password_hash" json:"-"`
+		}
+		metadata := NewDefaultMetadata(WithTagKey("db"), WithTagFallback("json"))
+
+		result, err := metadata.Parse(account{})
+		require.NoError(t, err)
+		field := result.MustFieldByName("PasswordHash")
+		assert.False(t, field.Ignored)
+		assert.Equal(t, "password_hash", field.Column)
+
+		values, err := metadata.ToMap(account{ID: 1, PasswordHash: "x"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"id": 1, "password_hash": "x"}, values)
+	})
+}
+
+type builderTimestamps struct {
+	CreatedAt string `schema:"created_at"`
+	UpdatedAt string `schema:"updated_at"`
+}
+
+type builderAudit struct {
+	builderTimestamps
+	UpdatedAt string `schema:"audit_updated_at"`
+	Author    string `schema:"author"`
+}
+
+type builderGeo struct {
+	Lat float64
+	Lng float64
+}
+
+type builderAddress struct {
+	City    string
+	ZipCode string `schema:"zip"`
+	builderGeo
+}
+
+func TestMetadataBuilder_BuildStructMetadata_EmbeddedPrefix(t *testing.T) {
+	type plain struct {
+		ID int
+		builderAddress
+	}
+
+	type customPrefix struct {
+		ID             int
+		builderAddress `schema:",prefix=addr_"`
+	}
+
+	tests := []struct {
+		name     string
+		typ      reflect.Type
+		opts     []MetadataOption
+		expected []string
+	}{
+		{
+			name:     "flat by default",
+			typ:      reflect.TypeOf(plain{}),
+			expected: []string{"id", "city", "zip", "lat", "lng"},
+		},
+		{
+			name:     "prefix from embedded field name",
+			typ:      reflect.TypeOf(plain{}),
+			opts:     []MetadataOption{WithEmbeddedPrefix(true)},
+			expected: []string{"id", "builder_address_city", "builder_address_zip", "builder_address_builder_geo_lat", "builder_address_builder_geo_lng"},
+		},
+		{
+			name:     "disabled",
+			typ:      reflect.TypeOf(plain{}),
+			opts:     []MetadataOption{WithEmbeddedPrefix(false)},
+			expected: []string{"id", "city", "zip", "lat", "lng"},
+		},
+		{
+			name:     "tag option without WithEmbeddedPrefix",
+			typ:      reflect.TypeOf(customPrefix{}),
+			expected: []string{"id", "addr_city", "addr_zip", "addr_lat", "addr_lng"},
+		},
+		{
+			name:     "tag option takes precedence",
+			typ:      reflect.TypeOf(customPrefix{}),
+			opts:     []MetadataOption{WithEmbeddedPrefix(true)},
+			expected: []string{"id", "addr_city", "addr_zip", "addr_builder_geo_lat", "addr_builder_geo_lng"},
+		},
+		{
+			name:     "prefix applied after naming strategy",
+			typ:      reflect.TypeOf(plain{}),
+			opts:     []MetadataOption{WithEmbeddedPrefix(true), WithNamingStrategy(upperNaming{})},
+			expected: []string{"ID", "BUILDERADDRESS_CITY", "BUILDERADDRESS_zip", "BUILDERADDRESS_BUILDERGEO_LAT", "BUILDERADDRESS_BUILDERGEO_LNG"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := newMetadataBuilder(NewDefaultTagParserRegistry(), tt.opts...)
+
+			result, err := builder.buildStructMetadata(tt.typ)
+
+			require.NoError(t, err)
+			columns := make([]string, 0, len(result.Fields))
+			for _, field := range result.Fields {
+				columns = append(columns, field.Column)
+			}
+			assert.Equal(t, tt.expected, columns)
+
+			_, ok := result.FieldByColumn(tt.expected[1])
+			assert.True(t, ok)
+		})
+	}
+}
+
+type builderSelfEmbedding struct {
+	*builderSelfEmbedding
+	Name string `schema:"name"`
+}
+
+func TestMetadataBuilder_BuildStructMetadata_FlattensEmbeddedStructs(t *testing.T) {
+	type testStruct struct {
+		ID int `schema:"id"`
+		builderAudit
+		Timestamps *builderTimestamps `schema:"timestamps"` // named field, not flattened
+	}
+
+	builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+	typ := reflect.TypeOf(testStruct{})
+
+	result, err := builder.buildStructMetadata(typ)
+
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(result.Fields))
+	for _, field := range result.Fields {
+		names = append(names, field.StructFieldName)
+	}
+	assert.Equal(t, []string{"ID", "CreatedAt", "UpdatedAt", "Author", "Timestamps"}, names)
+
+	// Promoted two levels deep
+	createdAt, ok := result.Field("CreatedAt")
+	require.True(t, ok)
+	assert.Equal(t, []int{1, 0, 0}, createdAt.IndexPath)
+	assert.Equal(t, 0, createdAt.Index)
+
+	// Shallower UpdatedAt from builderAudit shadows the one from builderTimestamps
+	updatedAt, ok := result.Field("UpdatedAt")
+	require.True(t, ok)
+	assert.Equal(t, []int{1, 1}, updatedAt.IndexPath)
+	assert.Equal(t, "audit_updated_at", updatedAt.Tag.Name)
+
+	// Named struct field stays a single field
+	timestamps, ok := result.Field("Timestamps")
+	require.True(t, ok)
+	assert.Equal(t, []int{2}, timestamps.IndexPath)
+	assert.Equal(t, reflect.TypeOf(&builderTimestamps{}), timestamps.Type)
+	assert.False(t, timestamps.Embedded)
+
+	// Index paths resolve through reflection
+	value := reflect.ValueOf(testStruct{builderAudit: builderAudit{
+		builderTimestamps: builderTimestamps{CreatedAt: "yesterday"},
+		UpdatedAt:         "today",
+	}})
+	assert.Equal(t, "yesterday", value.FieldByIndex(createdAt.IndexPath).String())
+	assert.Equal(t, "today", value.FieldByIndex(updatedAt.IndexPath).String())
+}
+
+func TestMetadataBuilder_BuildStructMetadata_AmbiguousPromotedFieldsDropped(t *testing.T) {
+	type first struct {
+		Name string `schema:"first_name"`
+		One  string `schema:"one"`
+	}
+	type second struct {
+		Name string `schema:"second_name"`
+		Two  string `schema:"two"`
+	}
+	type testStruct struct {
+		first
+		*second
+	}
+
+	builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+	result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+	require.NoError(t, err)
+	require.Len(t, result.Fields, 2)
+
+	_, ok := result.Field("Name")
+	assert.False(t, ok, "ambiguous names at the same depth are not promoted")
+
+	two, ok := result.Field("Two")
+	require.True(t, ok)
+	assert.Equal(t, []int{1, 1}, two.IndexPath)
+}
+
+func TestMetadataBuilder_BuildStructMetadata_SourceTag(t *testing.T) {
+	type base struct {
+		CreatedAt string `db:"created"`
+	}
+	type testStruct struct {
+		ID       int    `schema:"id" json:"identifier"`
+		Email    string `schema:",omitempty" json:"email_address"`
+		Nickname string `db:"nick" json:"nickname"`
+		Login    string `json:",omitempty"`
+		Renamed  string `schema:"renamed"`
+		Secret   string `schema:"-"`
+		base
+	}
+
+	builder := newMetadataBuilder(NewDefaultTagParserRegistry(),
+		WithTagFallback("db", "json"),
+		WithColumnOverrides(reflect.TypeOf(testStruct{}), map[string]string{"Renamed": "new_name"}),
+	)
+
+	result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+	require.NoError(t, err)
+
+	tests := []struct {
+		field      string
+		wantColumn string
+		wantSource string
+	}{
+		{field: "ID", wantColumn: "id", wantSource: "schema"},
+		{field: "Email", wantColumn: "email_address", wantSource: "json"},
+		{field: "Nickname", wantColumn: "nick", wantSource: "db"},
+		{field: "Login", wantColumn: "login", wantSource: SourceNaming},
+		{field: "Renamed", wantColumn: "new_name", wantSource: SourceOverride},
+		{field: "Secret", wantColumn: "", wantSource: ""},
+		{field: "CreatedAt", wantColumn: "created", wantSource: "db"},
+	}
+	for _, tt := range tests {
+		field := result.MustFieldByName(tt.field)
+		assert.Equal(t, tt.wantColumn, field.Column, tt.field)
+		assert.Equal(t, tt.wantSource, field.SourceTag, tt.field)
+	}
+	email := result.MustFieldByName("Email")
+	assert.Contains(t, email.String(), "source=json")
+}
+
+func TestMetadataBuilder_BuildStructMetadata_WithCollectWarnings(t *testing.T) {
+	type embeddedBase struct {
+		UpdatedAt string
+	}
+	type testStruct struct {
+		ID      int    `schema:"id,pk,uniq"`
+		Email   string `schema:"email,size=255,omitempty"`
+		Name    string
+		Secret  string `schema:"-"`
+		private string
+		embeddedBase
+		time.Time
+	}
+
+	t.Run("collects warnings", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithCollectWarnings())
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			`field "ID": unknown tag option "uniq"`,
+			`field "Email": unknown tag option "size"`,
+			`field "Name": missing "schema" tag`,
+			`field "UpdatedAt": missing "schema" tag`,
+		}, result.Warnings())
+	})
+
+	t.Run("strict options report errors instead", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithCollectWarnings(), WithRequireTag(),
+			WithStrictTags(), WithKnownOptions("uniq", "size"))
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+
+		type tagged struct {
+			ID int `schema:"id,uniq"`
+		}
+		result, err = builder.buildStructMetadata(reflect.TypeOf(tagged{}))
+		require.NoError(t, err)
+		assert.Nil(t, result.Warnings())
+	})
+
+	t.Run("parameter options are not reported", func(t *testing.T) {
+		type request struct {
+			IDs []int `schema:"ids,location=query,style=form,explode=true"`
+		}
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithCollectWarnings())
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(request{}))
+
+		require.NoError(t, err)
+		assert.Nil(t, result.Warnings())
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+		assert.Nil(t, result.Warnings())
+	})
+
+	t.Run("returns a copy", func(t *testing.T) {
+		result, err := NewDefaultMetadata(WithCollectWarnings()).Parse(testStruct{})
+		require.NoError(t, err)
+
+		warnings := result.Warnings()
+		warnings[0] = "changed"
+		assert.Equal(t, `field "ID": unknown tag option "uniq"`, result.Warnings()[0])
+	})
+}
+
+func TestMetadataBuilder_BuildStructMetadata_WithFieldHook(t *testing.T) {
+	type address struct {
+		City string
+	}
+	type audit struct {
+		CreatedBy string
+	}
+	type testStruct struct {
+		ID      int    `schema:"id"`
+		Email   string `schema:"email"`
+		Secret  string `schema:"-"`
+		Address address
+		audit
+	}
+
+	t.Run("sees final columns and may change fields", func(t *testing.T) {
+		var seen []string
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(),
+			WithEmbeddedPrefix(true),
+			WithColumnOverrides(reflect.TypeOf(testStruct{}), map[string]string{"Email": "email_address"}),
+			WithNestedMetadata(),
+			WithImmutable(),
+			WithFieldHook(func(field *FieldMetadata) error {
+				seen = append(seen, field.StructFieldName+"="+field.Column)
+
+				return nil
+			}),
+			WithFieldHook(nil),
+			WithFieldHook(func(field *FieldMetadata) error {
+				if field.StructFieldName == "ID" {
+					field.Column = "user_id"
+					field.Required = true
+				}
+				field.SetExtra("hooked", true)
+
+				return nil
+			}),
+		)
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"City=city",
+			"ID=id", "Email=email_address", "Secret=", "Address=address", "CreatedBy=audit_created_by",
+		}, seen, "nested fields are hooked as their metadata is built")
+
+		id, ok := result.FieldByColumn("user_id")
+		require.True(t, ok, "column changes are indexed")
+		assert.True(t, id.Required)
+		hooked, _ := id.GetExtra("hooked")
+		assert.Equal(t, true, hooked, "hooks run before the metadata is frozen")
+		assert.True(t, result.IsImmutable())
+	})
+
+	t.Run("error aborts the parse", func(t *testing.T) {
+		errRejected := errors.New("rejected")
+		calls := 0
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithFieldHook(func(field *FieldMetadata) error {
+			calls++
+			if field.StructFieldName == "Email" {
+				return errRejected
+			}
+
+			return nil
+		}))
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.ErrorIs(t, err, errRejected)
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "field hook: field Email: rejected")
+		assert.Equal(t, 2, calls, "later fields are not hooked")
+	})
+}
+
+func TestMetadataBuilder_BuildStructMetadata_WithConflictPolicy(t *testing.T) {
+	type person struct {
+		Name string
+		Age  int
+	}
+	type company struct {
+		Name string
+		VAT  string `schema:"vat"`
+	}
+	type testStruct struct {
+		person
+		company
+	}
+
+	tests := []struct {
+		name      string
+		opts      []MetadataOption
+		wantIndex []int
+	}{
+		{name: "error by default"},
+		{name: "explicit error", opts: []MetadataOption{WithConflictPolicy(ConflictError)}},
+		{name: "empty policy is ignored", opts: []MetadataOption{WithConflictPolicy("")}},
+		{name: "keep first", opts: []MetadataOption{WithConflictPolicy(ConflictKeepFirst)}, wantIndex: []int{0, 0}},
+		{name: "keep last", opts: []MetadataOption{WithConflictPolicy(ConflictKeepLast)}, wantIndex: []int{1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := newMetadataBuilder(NewDefaultTagParserRegistry(), tt.opts...)
+
+			result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+			if tt.wantIndex == nil {
+				require.Error(t, err)
+				assert.Equal(t, []ValidationRule{ErrDuplicateColumn}, fieldErrorRules(err))
+				assert.ErrorContains(t, err, `column "name" at index [1 0] conflicts with field "Name" at index [0 0]`)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result.Fields, 3, "Name, Age and VAT")
+			name := result.MustFieldByName("Name")
+			assert.Equal(t, tt.wantIndex, name.IndexPath)
+			assert.Equal(t, "name", name.Column)
+		})
+	}
+
+	t.Run("shadowed fields do not conflict", func(t *testing.T) {
+		type shadowing struct {
+			Name string `schema:"full_name"`
+			person
+			company
+		}
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(shadowing{}))
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{0}, result.MustFieldByName("Name").IndexPath)
+	})
+
+	t.Run("embedded prefixes keep columns apart", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithEmbeddedPrefix(true))
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+		_, ok := result.Field("Name")
+		assert.False(t, ok, "different columns leave Go's ambiguity rule in charge")
+	})
+}
+
+func TestMetadataBuilder_BuildStructMetadata_EmbeddingCycle(t *testing.T) {
+	builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+	result, err := builder.buildStructMetadata(reflect.TypeOf(builderSelfEmbedding{}))
+
+	require.NoError(t, err)
+	_, ok := result.Field("Name")
+	assert.True(t, ok)
+}
+
+// upperNaming is a custom naming strategy for testing.
+type upperNaming struct{}
+
+func (upperNaming) Column(fieldName string) string {
+	return strings.ToUpper(fieldName)
+}
+
+func TestMetadataBuilder_BuildStructMetadata_ResolvesColumns(t *testing.T) {
+	type testStruct struct {
+		UserID    int    `schema:"id"`
+		FirstName string `schema:",required"`
+		LastName  string
+		Skipped   string `schema:"-"`
+	}
+
+	t.Run("default snake_case", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+		assert.Equal(t, "id", result.MustFieldByName("UserID").Column)
+		assert.Equal(t, "first_name", result.MustFieldByName("FirstName").Column)
+		assert.Equal(t, "last_name", result.MustFieldByName("LastName").Column)
+		assert.Empty(t, result.MustFieldByName("Skipped").Column)
+	})
+
+	t.Run("custom naming strategy", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithNamingStrategy(upperNaming{}))
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+		assert.Equal(t, "id", result.MustFieldByName("UserID").Column, "tag name takes precedence")
+		assert.Equal(t, "FIRSTNAME", result.MustFieldByName("FirstName").Column)
+		assert.Equal(t, "LASTNAME", result.MustFieldByName("LastName").Column)
+	})
+}
+
+func TestMetadataBuilder_BuildStructMetadata_PointerFields(t *testing.T) {
+	type testStruct struct {
+		Value        string
+		Pointer      *string
+		DoublePtr    **int
+		StructPtr    *builderTimestamps
+		PointerSlice *[]string
+	}
+
+	builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+	result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+	require.NoError(t, err)
+
+	tests := []struct {
+		field       string
+		wantPointer bool
+		wantType    reflect.Type
+		wantElem    reflect.Type
+	}{
+		{field: "Value", wantPointer: false, wantType: reflect.TypeOf(""), wantElem: reflect.TypeOf("")},
+		{field: "Pointer", wantPointer: true, wantType: reflect.TypeOf((*string)(nil)), wantElem: reflect.TypeOf("")},
+		{field: "DoublePtr", wantPointer: true, wantType: reflect.TypeOf((**int)(nil)), wantElem: reflect.TypeOf(0)},
+		{field: "StructPtr", wantPointer: true, wantType: reflect.TypeOf(&builderTimestamps{}), wantElem: reflect.TypeOf(builderTimestamps{})},
+		{field: "PointerSlice", wantPointer: true, wantType: reflect.TypeOf(&[]string{}), wantElem: reflect.TypeOf("")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field := result.MustFieldByName(tt.field)
+			assert.Equal(t, tt.wantPointer, field.IsPointer)
+			assert.Equal(t, tt.wantType, field.Type)
+			assert.Equal(t, tt.wantElem, field.ElemType)
+		})
+	}
+}
+
+type builderItem struct {
+	SKU string `schema:"sku"`
+}
+
+type builderCategory struct {
+	Name     string
+	Children []builderCategory
+}
+
+func TestMetadataBuilder_BuildStructMetadata_CollectionFields(t *testing.T) {
+	type testStruct struct {
+		Items    []builderItem
+		ItemPtrs *[]*builderItem
+		Hash     [16]byte
+		Name     string
+	}
+
+	t.Run("element types", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+
+		items := result.MustFieldByName("Items")
+		assert.True(t, items.IsSlice)
+		assert.False(t, items.IsArray)
+		assert.Equal(t, reflect.TypeOf(builderItem{}), items.ElemType)
+		assert.Nil(t, items.ElemMetadata, "nested metadata is opt-in")
+
+		itemPtrs := result.MustFieldByName("ItemPtrs")
+		assert.True(t, itemPtrs.IsSlice)
+		assert.Equal(t, reflect.TypeOf(&builderItem{}), itemPtrs.ElemType)
+
+		hash := result.MustFieldByName("Hash")
+		assert.True(t, hash.IsArray)
+		assert.False(t, hash.IsSlice)
+		assert.Equal(t, reflect.TypeOf(byte(0)), hash.ElemType)
+
+		name := result.MustFieldByName("Name")
+		assert.False(t, name.IsSlice)
+		assert.False(t, name.IsArray)
+	})
+
+	t.Run("array lengths", func(t *testing.T) {
+		type lengths struct {
+			UUID     [16]byte
+			Payload  []byte
+			Triple   [3]int
+			TriplePt *[3]int
+			Empty    [0]int
+			Count    int
+		}
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(lengths{}))
+
+		require.NoError(t, err)
+
+		tests := []struct {
+			field     string
+			wantArray bool
+			wantSlice bool
+			wantLen   int
+			wantElem  reflect.Type
+		}{
+			{field: "UUID", wantArray: true, wantLen: 16, wantElem: reflect.TypeOf(byte(0))},
+			{field: "Payload", wantSlice: true, wantElem: reflect.TypeOf(byte(0))},
+			{field: "Triple", wantArray: true, wantLen: 3, wantElem: reflect.TypeOf(0)},
+			{field: "TriplePt", wantArray: true, wantLen: 3, wantElem: reflect.TypeOf(0)},
+			{field: "Empty", wantArray: true, wantElem: reflect.TypeOf(0)},
+			{field: "Count", wantElem: reflect.TypeOf(0)},
+		}
+		for _, tt := range tests {
+			field := result.MustFieldByName(tt.field)
+			assert.Equal(t, tt.wantArray, field.IsArray, tt.field)
+			assert.Equal(t, tt.wantSlice, field.IsSlice, tt.field)
+			assert.Equal(t, tt.wantLen, field.ArrayLen, tt.field)
+			assert.Equal(t, tt.wantElem, field.ElemType, tt.field)
+		}
+	})
+
+	t.Run("nested metadata", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithNestedMetadata())
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+
+		for _, name := range []string{"Items", "ItemPtrs"} {
+			elemMeta := result.MustFieldByName(name).ElemMetadata
+			require.NotNil(t, elemMeta, name)
+			assert.Equal(t, reflect.TypeOf(builderItem{}), elemMeta.Type)
+			assert.Equal(t, "sku", elemMeta.MustFieldByName("SKU").Column)
+		}
+
+		assert.Nil(t, result.MustFieldByName("Hash").ElemMetadata)
+		assert.Nil(t, result.MustFieldByName("Name").ElemMetadata)
+	})
+
+}
+
+type builderListNode struct {
+	Value int
+	Next  *builderListNode
+}
+
+type builderTreeNode struct {
+	Label    string
+	Parent   *builderTreeNode
+	Children []*builderTreeNode
+	Meta     builderTreeMeta
+}
+
+type builderTreeMeta struct {
+	Owner *builderTreeNode
+}
+
+func TestMetadataBuilder_BuildStructMetadata_RecursiveTypes(t *testing.T) {
+	builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithNestedMetadata())
+
+	t.Run("linked list", func(t *testing.T) {
+		result, err := builder.buildStructMetadata(reflect.TypeOf(builderListNode{}))
+
+		require.NoError(t, err)
+		assert.Same(t, result, result.MustFieldByName("Next").ElemMetadata)
+	})
+
+	t.Run("tree", func(t *testing.T) {
+		result, err := builder.buildStructMetadata(reflect.TypeOf(builderTreeNode{}))
+
+		require.NoError(t, err)
+		assert.Same(t, result, result.MustFieldByName("Parent").ElemMetadata)
+		assert.Same(t, result, result.MustFieldByName("Children").ElemMetadata)
+
+		// Indirect cycle through another struct resolves back to the root as well
+		meta := result.MustFieldByName("Meta").ElemMetadata
+		require.NotNil(t, meta)
+		assert.Same(t, result, meta.MustFieldByName("Owner").ElemMetadata)
+	})
+
+	t.Run("slice of self", func(t *testing.T) {
+		result, err := builder.buildStructMetadata(reflect.TypeOf(builderCategory{}))
+
+		require.NoError(t, err)
+		assert.Same(t, result, result.MustFieldByName("Children").ElemMetadata)
+	})
+}
+
+// builderMoney is a custom struct type registered as scalar in tests.
+type builderMoney struct {
+	Amount   int64
+	Currency string
+}
+
+func TestMetadataBuilder_BuildStructMetadata_ScalarTypes(t *testing.T) {
+	type testStruct struct {
+		time.Time
+		UpdatedAt *time.Time
+		Price     builderMoney
+		Discount  *builderMoney
+		Name      string
+	}
+
+	t.Run("time.Time is pre-registered", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithNestedMetadata())
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+
+		// Embedded time.Time stays a single field instead of being flattened
+		embedded, ok := result.FieldByName("Time")
+		require.True(t, ok)
+		assert.True(t, embedded.IsScalar)
+		assert.True(t, embedded.Embedded)
+		assert.Nil(t, embedded.ElemMetadata)
+
+		updatedAt := result.MustFieldByName("UpdatedAt")
+		assert.True(t, updatedAt.IsScalar)
+		assert.Nil(t, updatedAt.ElemMetadata)
+
+		price := result.MustFieldByName("Price")
+		assert.False(t, price.IsScalar)
+		assert.NotNil(t, price.ElemMetadata)
+
+		assert.False(t, result.MustFieldByName("Name").IsScalar)
+	})
+
+	t.Run("custom scalar types", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(),
+			WithNestedMetadata(),
+			WithScalarTypes(reflect.TypeOf(builderMoney{}), nil),
+		)
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+
+		for _, name := range []string{"Time", "UpdatedAt", "Price", "Discount"} {
+			field := result.MustFieldByName(name)
+			assert.True(t, field.IsScalar, name)
+			assert.Nil(t, field.ElemMetadata, name)
+		}
+	})
+}
+
+func TestMetadataBuilder_BuildStructMetadata_ExposesStructField(t *testing.T) {
+	type testStruct struct {
+		Name string `schema:"name" validate:"required"`
+		builderTimestamps
+	}
+
+	builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+	result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+	require.NoError(t, err)
+
+	name := result.MustFieldByName("Name")
+	assert.Equal(t, "Name", name.StructField.Name)
+	assert.Equal(t, "required", name.StructField.Tag.Get("validate"))
+	assert.Empty(t, name.StructField.PkgPath)
+
+	// Promoted fields expose the field as declared on the embedded struct
+	createdAt := result.MustFieldByName("CreatedAt")
+	assert.Equal(t, reflect.TypeOf(builderTimestamps{}).Field(0), createdAt.StructField)
+}
+
+type builderInternal struct {
+	Visible string `schema:"visible"`
+	hidden  string //nolint:unused // Test field - verified via metadata only
+}
+
+func TestMetadataBuilder_BuildStructMetadata_UnexportedFields(t *testing.T) {
+	type testStruct struct {
+		Name   string `schema:"name"`
+		secret string //nolint:unused // Test field - verified via metadata only
+		builderInternal
+	}
+
+	t.Run("skipped by default", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+		require.Len(t, result.Fields, 2)
+		assert.True(t, result.MustFieldByName("Name").Exported)
+		// Exported fields promoted from an unexported embedded struct are kept
+		assert.True(t, result.MustFieldByName("Visible").Exported)
+		_, ok := result.FieldByName("secret")
+		assert.False(t, ok)
+		_, ok = result.FieldByName("hidden")
+		assert.False(t, ok)
+	})
+
+	t.Run("included on request", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithIncludeUnexported())
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+		require.Len(t, result.Fields, 4)
+
+		secret := result.MustFieldByName("secret")
+		assert.False(t, secret.Exported)
+		assert.Equal(t, []int{1}, secret.IndexPath)
+
+		hidden := result.MustFieldByName("hidden")
+		assert.False(t, hidden.Exported)
+		assert.Equal(t, []int{2, 1}, hidden.IndexPath)
+
+		assert.True(t, result.MustFieldByName("Visible").Exported)
+	})
+}
+
+func TestMetadataBuilder_BuildStructMetadata_MapFields(t *testing.T) {
+	type testStruct struct {
+		Counts  map[string]int
+		Labels  *map[int][]string
+		Name    string
+		Entries []map[string]int
+	}
+
+	builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+	result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+	require.NoError(t, err)
+
+	counts := result.MustFieldByName("Counts")
+	assert.True(t, counts.IsMap)
+	assert.Equal(t, reflect.TypeOf(""), counts.KeyType)
+	assert.Equal(t, reflect.TypeOf(0), counts.ValueType)
+	assert.Equal(t, CategoryMap, counts.Category())
+
+	labels := result.MustFieldByName("Labels")
+	assert.True(t, labels.IsMap)
+	assert.Equal(t, reflect.TypeOf(0), labels.KeyType)
+	assert.Equal(t, reflect.TypeOf([]string{}), labels.ValueType)
+	assert.Equal(t, CategoryMap, labels.Category())
+
+	for _, name := range []string{"Name", "Entries"} {
+		field := result.MustFieldByName(name)
+		assert.False(t, field.IsMap, name)
+		assert.Nil(t, field.KeyType, name)
+		assert.Nil(t, field.ValueType, name)
+		assert.NotEqual(t, CategoryMap, field.Category(), name)
+	}
+}
+
+func TestMetadataBuilder_BuildStructMetadata_InterfaceFields(t *testing.T) {
+	type testStruct struct {
+		fmt.Stringer
+		Payload any
+		Reader  io.Reader
+		List    []any
+		Name    string
+	}
+
+	builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithNestedMetadata())
+
+	result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+	require.NoError(t, err)
+
+	for _, name := range []string{"Stringer", "Payload", "Reader"} {
+		field := result.MustFieldByName(name)
+		assert.True(t, field.IsInterface, name)
+		assert.Equal(t, CategoryOther, field.Category(), name)
+		assert.Nil(t, field.ElemMetadata, name)
+	}
+
+	// Embedded interfaces are kept as a single field, never flattened into their method set
+	stringer := result.MustFieldByName("Stringer")
+	assert.True(t, stringer.Embedded)
+	assert.Equal(t, []int{0}, stringer.IndexPath)
+
+	for _, name := range []string{"List", "Name"} {
+		assert.False(t, result.MustFieldByName(name).IsInterface, name)
+	}
+}
+
+type builderInternalAudit struct {
+	CreatedBy string `schema:"created_by"`
+	DebugInfo string `schema:"debug_info" visibility:"internal"`
+}
+
+func TestMetadataBuilder_BuildStructMetadata_WithFieldFilter(t *testing.T) {
+	type testStruct struct {
+		ID    int    `schema:"id"`
+		Notes string `schema:"notes" visibility:"internal"`
+		*builderInternalAudit
+		Email string `schema:"email"`
+	}
+
+	public := func(field reflect.StructField) bool {
+		return field.Tag.Get("visibility") != "internal"
+	}
+	builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithFieldFilter(public))
+
+	result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(result.Fields))
+	for _, field := range result.Fields {
+		names = append(names, field.StructFieldName)
+	}
+	assert.Equal(t, []string{"ID", "CreatedBy", "Email"}, names)
+
+	// Remaining fields keep their index paths into the original struct
+	assert.Equal(t, []int{2, 0}, result.MustFieldByName("CreatedBy").IndexPath)
+	assert.Equal(t, []int{3}, result.MustFieldByName("Email").IndexPath)
+
+	_, ok := result.Field("DebugInfo")
+	assert.False(t, ok)
+	_, ok = result.FieldByColumn("notes")
+	assert.False(t, ok)
+
+	t.Run("nil filter is ignored", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithFieldFilter(nil))
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+		assert.Len(t, result.Fields, 5)
+	})
+}
+
+// builderReadCloser is an unexported interface embedded in tests.
+type builderReadCloser interface {
+	io.Reader
+	io.Closer
+}
+
+func TestMetadataBuilder_BuildStructMetadata_EmbeddedInterfaces(t *testing.T) {
+	type testStruct struct {
+		io.Reader
+		builderReadCloser
+		Name string `schema:"name,location=query"`
+	}
+
+	t.Run("exported interface kept, unexported skipped", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithNestedMetadata())
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+		require.Len(t, result.Fields, 2)
+
+		reader := result.MustFieldByName("Reader")
+		assert.True(t, reader.Embedded)
+		assert.True(t, reader.IsInterface)
+		assert.Equal(t, []int{0}, reader.IndexPath)
+		assert.Nil(t, reader.ElemMetadata)
+
+		// No promoted fields from the interfaces' method sets
+		for _, name := range []string{"Read", "Close", "builderReadCloser"} {
+			_, ok := result.Field(name)
+			assert.False(t, ok, name)
+		}
+	})
+
+	t.Run("unexported interface included on request", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithIncludeUnexported())
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+		readCloser := result.MustFieldByName("builderReadCloser")
+		assert.True(t, readCloser.Embedded)
+		assert.True(t, readCloser.IsInterface)
+		assert.False(t, readCloser.Exported)
+	})
+
+	t.Run("decoding and ToMap do not panic", func(t *testing.T) {
+		var decoded testStruct
+		request := httptest.NewRequest(http.MethodGet, "/?name=Alice", nil)
+
+		require.NoError(t, NewDefaultCodec().DecodeRequest(request, nil, &decoded))
+		assert.Equal(t, "Alice", decoded.Name)
+
+		result, err := NewDefaultMetadata().ToMap(testStruct{Reader: strings.NewReader("x"), Name: "Bob"})
+		require.NoError(t, err)
+		assert.Equal(t, "Bob", result["name"])
+		assert.NotNil(t, result["reader"])
+	})
+}
+
+type BuilderLevel3 struct {
+	C string
+}
+
+type BuilderLevel2 struct {
+	BuilderLevel3
+	B string
+}
+
+type BuilderLevel1 struct {
+	BuilderLevel2
+	A string
+}
+
+func TestMetadataBuilder_BuildStructMetadata_WithMaxEmbedDepth(t *testing.T) {
+	type testStruct struct {
+		BuilderLevel1
+		ID int
+	}
+
+	tests := []struct {
+		name         string
+		opts         []MetadataOption
+		expected     []string
+		boundary     string
+		boundaryPath []int
+	}{
+		{
+			name:     "unlimited by default",
+			expected: []string{"C", "B", "A", "ID"},
+		},
+		{
+			name:     "negative is unlimited",
+			opts:     []MetadataOption{WithMaxEmbedDepth(-1)},
+			expected: []string{"C", "B", "A", "ID"},
+		},
+		{
+			name:         "depth 0",
+			opts:         []MetadataOption{WithMaxEmbedDepth(0)},
+			expected:     []string{"BuilderLevel1", "ID"},
+			boundary:     "BuilderLevel1",
+			boundaryPath: []int{0},
+		},
+		{
+			name:         "depth 1",
+			opts:         []MetadataOption{WithMaxEmbedDepth(1)},
+			expected:     []string{"BuilderLevel2", "A", "ID"},
+			boundary:     "BuilderLevel2",
+			boundaryPath: []int{0, 0},
+		},
+		{
+			name:         "depth 2",
+			opts:         []MetadataOption{WithMaxEmbedDepth(2)},
+			expected:     []string{"BuilderLevel3", "B", "A", "ID"},
+			boundary:     "BuilderLevel3",
+			boundaryPath: []int{0, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := newMetadataBuilder(NewDefaultTagParserRegistry(), tt.opts...)
+
+			result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+			require.NoError(t, err)
+			names := make([]string, 0, len(result.Fields))
+			for _, field := range result.Fields {
+				names = append(names, field.StructFieldName)
+			}
+			assert.Equal(t, tt.expected, names)
+
+			if tt.boundary != "" {
+				boundary := result.MustFieldByName(tt.boundary)
+				assert.True(t, boundary.Embedded)
+				assert.True(t, boundary.IsStruct)
+				assert.Equal(t, tt.boundaryPath, boundary.IndexPath)
+			}
+		})
+	}
+}
+
+type builderPrice struct {
+	Cents    int64
+	Currency string
+}
+
+func (m builderPrice) Value() (driver.Value, error) {
+	return fmt.Sprintf("%d %s", m.Cents, m.Currency), nil
+}
+
+type builderAuditTrail struct {
+	Entries []string
+}
+
+func (a *builderAuditTrail) Scan(src any) error {
+	a.Entries = append(a.Entries, fmt.Sprint(src))
+
+	return nil
+}
+
+func TestMetadataBuilder_BuildStructMetadata_ScannerValuer(t *testing.T) {
+	type testStruct struct {
+		sql.NullInt64
+		Nickname sql.NullString    `schema:"nickname"`
+		Price    *builderPrice     `schema:"price"`
+		Audit    builderAuditTrail `schema:"audit"`
+		Valuer   driver.Valuer     `schema:"valuer"`
+		Address  builderAddress    `schema:"address"`
+	}
+
+	builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithNestedMetadata())
+
+	result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		scanner bool
+		valuer  bool
+	}{
+		{name: "NullInt64", scanner: true, valuer: true},
+		{name: "Nickname", scanner: true, valuer: true},
+		{name: "Price", valuer: true},
+		{name: "Audit", scanner: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := result.MustFieldByName(tt.name)
+			assert.Equal(t, tt.scanner, field.IsScanner)
+			assert.Equal(t, tt.valuer, field.IsValuer)
+			assert.True(t, field.IsScalar)
+			assert.Nil(t, field.ElemMetadata, "scalars are not parsed into nested metadata")
+		})
+	}
+
+	// Embedded sql.NullInt64 is kept as a single field instead of promoting Int64 and Valid
+	embedded := result.MustFieldByName("NullInt64")
+	assert.True(t, embedded.Embedded)
+	assert.Equal(t, []int{0}, embedded.IndexPath)
+	_, ok := result.FieldByName("Valid")
+	assert.False(t, ok)
+
+	// Interface types are not flagged; only their dynamic values could implement the interfaces
+	valuer := result.MustFieldByName("Valuer")
+	assert.False(t, valuer.IsValuer)
+	assert.False(t, valuer.IsScalar)
+
+	address := result.MustFieldByName("Address")
+	assert.False(t, address.IsScanner || address.IsValuer || address.IsScalar)
+	assert.NotNil(t, address.ElemMetadata)
+
+	nickname := result.MustFieldByName("Nickname")
+	assert.Equal(t, "Nickname column=nickname type=sql.NullString index=[1] source=schema flags=struct,scalar,scanner,valuer",
+		nickname.String())
+}
+
+func TestMetadataBuilder_BuildStructMetadata_WithColumnOverrides(t *testing.T) {
+	type testStruct struct {
+		ID       int    `schema:"id"`
+		UserName string `schema:"user_name"`
+		Email    string
+		Secret   string `schema:"-"`
+		builderAddress
+	}
+
+	t.Run("overrides take precedence", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(),
+			WithEmbeddedPrefix(true),
+			WithColumnOverrides(reflect.TypeOf(testStruct{}), map[string]string{"UserName": "USR_NM", "Email": "EMAIL_ADDR"}),
+			WithColumnOverrides(reflect.TypeOf(&testStruct{}), map[string]string{"City": "TOWN", "Secret": "secret", "": "x", "ID": ""}),
+			WithColumnOverrides(nil, map[string]string{"ID": "ignored"}),
+		)
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+		assert.Equal(t, "id", result.MustFieldByName("ID").Column)
+		assert.Equal(t, "USR_NM", result.MustFieldByName("UserName").Column, "override beats tag name")
+		assert.Equal(t, "EMAIL_ADDR", result.MustFieldByName("Email").Column, "override beats naming strategy")
+		assert.Equal(t, "TOWN", result.MustFieldByName("City").Column, "embedded prefix is not applied")
+		assert.Equal(t, "builder_address_zip", result.MustFieldByName("ZipCode").Column)
+		assert.Empty(t, result.MustFieldByName("Secret").Column, "ignored fields keep no column")
+		_, ok := result.FieldByColumn("TOWN")
+		assert.True(t, ok)
+	})
+
+	t.Run("unknown field names fail", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(),
+			WithColumnOverrides(reflect.TypeOf(testStruct{}), map[string]string{"Usrname": "usr", "Emial": "mail", "Email": "mail"}))
+
+		_, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `column override for unknown field "Emial"`)
+		assert.Contains(t, err.Error(), `column override for unknown field "Usrname"`)
+	})
+
+	t.Run("colliding override", func(t *testing.T) {
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(),
+			WithColumnOverrides(reflect.TypeOf(testStruct{}), map[string]string{"Email": "id"}))
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+		assert.Contains(t, fieldErrorRules(result.Validate()), ErrDuplicateColumn)
+	})
+
+	t.Run("other types are unaffected", func(t *testing.T) {
+		type other struct {
+			X     int
+			Title string
+		}
+		metadata := NewDefaultMetadata(WithColumnOverrides(reflect.TypeOf(testStruct{}),
+			map[string]string{"UserName": "USR_NM", "Title": "headline"}))
+
+		_, err := metadata.Parse(testStruct{})
+		require.Error(t, err, "Title is not a field of testStruct")
+
+		result, err := metadata.Parse(other{})
+		require.NoError(t, err)
+		assert.Equal(t, "x", result.MustFieldByName("X").Column)
+		assert.Equal(t, "title", result.MustFieldByName("Title").Column)
+	})
+
+	t.Run("nested element types use their own overrides", func(t *testing.T) {
+		type parent struct {
+			Value int
+			Child builderListNode
+		}
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithNestedMetadata(),
+			WithColumnOverrides(reflect.TypeOf(parent{}), map[string]string{"Child": "node"}),
+			WithColumnOverrides(reflect.TypeOf(builderListNode{}), map[string]string{"Value": "val"}))
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(parent{}))
+
+		require.NoError(t, err)
+		assert.Equal(t, "value", result.MustFieldByName("Value").Column)
+		child := result.MustFieldByName("Child")
+		assert.Equal(t, "node", child.Column)
+		require.NotNil(t, child.ElemMetadata)
+		assert.Equal(t, "val", child.ElemMetadata.MustFieldByName("Value").Column)
+	})
+}
+
+type builderStamps struct {
+	CreatedBy string `schema:"created_by"`
+	UpdatedBy string `schema:"updated_by"`
+}
+
+func TestMetadataBuilder_BuildStructMetadata_InlineFields(t *testing.T) {
+	t.Run("without inline the field stays a struct", func(t *testing.T) {
+		type testStruct struct {
+			ID    int           `schema:"id"`
+			Audit builderStamps `schema:"audit"`
+		}
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+		require.Len(t, result.Fields, 2)
+		audit := result.MustFieldByName("Audit")
+		assert.True(t, audit.IsStruct)
+		assert.Equal(t, "audit", audit.Column)
+	})
+
+	t.Run("inline and squash promote fields", func(t *testing.T) {
+		type testStruct struct {
+			ID      int             `schema:"id"`
+			Audit   builderStamps   `schema:",inline"`
+			Address *builderAddress `schema:",squash,prefix=addr_"`
+		}
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry(), WithStrictTags())
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+		_, ok := result.FieldByName("Audit")
+		assert.False(t, ok, "the inline field itself is not kept")
+
+		createdBy := result.MustFieldByName("CreatedBy")
+		assert.Equal(t, []int{1, 0}, createdBy.IndexPath)
+		assert.Equal(t, "created_by", createdBy.Column)
+		assert.True(t, createdBy.IsNested)
+		assert.Equal(t, []int{1, 1}, result.MustFieldByName("UpdatedBy").IndexPath)
+
+		city := result.MustFieldByName("City")
+		assert.Equal(t, []int{2, 0}, city.IndexPath)
+		assert.Equal(t, "addr_city", city.Column)
+		assert.Equal(t, []int{2, 2, 0}, result.MustFieldByName("Lat").IndexPath)
+
+		value := testStruct{}
+		require.NoError(t, createdBy.SetValue(reflect.ValueOf(&value), "alice"))
+		require.NoError(t, city.SetValue(reflect.ValueOf(&value), "Oslo"))
+		assert.Equal(t, "alice", value.Audit.CreatedBy)
+		require.NotNil(t, value.Address, "inline pointers are allocated")
+		assert.Equal(t, "Oslo", value.Address.City)
+	})
+
+	t.Run("conflicts with parent fields", func(t *testing.T) {
+		type testStruct struct {
+			CreatedBy string        `schema:"creator"`
+			Audit     builderStamps `schema:",inline"`
+		}
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+		_, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.Error(t, err)
+		assert.Equal(t, []ValidationRule{ErrFieldConflict}, fieldErrorRules(err))
+		assert.Contains(t, err.Error(), `field "CreatedBy": field inlined from "Audit" conflicts with another field of`)
+	})
+
+	t.Run("sibling inline fields with prefixes", func(t *testing.T) {
+		type testStruct struct {
+			Home builderAddress `schema:",inline,prefix=home_"`
+			Work builderAddress `schema:",inline,prefix=work_"`
+		}
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.NoError(t, err)
+		homeCity, ok := result.FieldByColumn("home_city")
+		require.True(t, ok)
+		assert.Equal(t, []int{0, 0}, homeCity.IndexPath)
+		workCity, ok := result.FieldByColumn("work_city")
+		require.True(t, ok)
+		assert.Equal(t, []int{1, 0}, workCity.IndexPath)
+
+		value := testStruct{}
+		require.NoError(t, workCity.SetValue(reflect.ValueOf(&value), "Oslo"))
+		assert.Equal(t, "Oslo", value.Work.City)
+		assert.Empty(t, value.Home.City)
+	})
+
+	t.Run("sibling inline fields sharing columns", func(t *testing.T) {
+		type testStruct struct {
+			Created builderStamps `schema:",inline"`
+			Updated builderStamps `schema:",inline"`
+		}
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+		_, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{}))
+
+		require.Error(t, err)
+		assert.Equal(t, []ValidationRule{ErrDuplicateColumn, ErrDuplicateColumn}, fieldErrorRules(err))
+	})
+
+	t.Run("unexported fields are not inlined", func(t *testing.T) {
+		type testStruct struct {
+			ID    int           `schema:"id"`
+			audit builderStamps `schema:",inline"`
+		}
+		builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+
+		result, err := builder.buildStructMetadata(reflect.TypeOf(testStruct{audit: builderStamps{}}))
+
+		require.NoError(t, err)
+		assert.False(t, result.Has("CreatedBy"))
+	})
+}
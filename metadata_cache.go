@@ -8,8 +8,9 @@ import (
 
 // metadataCache provides caching for struct field metadata.
 type metadataCache struct {
-	cache   sync.Map // map[reflect.Type]*StructMetadata
-	builder *metadataBuilder
+	cache        sync.Map // map[reflect.Type]*StructMetadata
+	pointerViews sync.Map // map[*StructMetadata]*StructMetadata, keyed by the cached struct metadata
+	builder      *metadataBuilder
 }
 
 // newMetadataCache creates a new metadata cache.
@@ -40,3 +41,26 @@ func (c *metadataCache) get(typ reflect.Type) (*StructMetadata, error) {
 
 	return fields, nil
 }
+
+// pointerView returns the cached copy of structMeta with IsPointerRoot set. It shares Fields and the
+// lookup indexes with structMeta, so it costs one allocation per struct type.
+func (c *metadataCache) pointerView(structMeta *StructMetadata) *StructMetadata {
+	if cached, ok := c.pointerViews.Load(structMeta); ok {
+		if view, ok := cached.(*StructMetadata); ok {
+			return view
+		}
+	}
+
+	view := *structMeta
+	view.IsPointerRoot = true
+	actual, _ := c.pointerViews.LoadOrStore(structMeta, &view)
+	result, _ := actual.(*StructMetadata)
+
+	return result
+}
+
+// clear removes all cached struct metadata.
+func (c *metadataCache) clear() {
+	c.cache.Clear()
+	c.pointerViews.Clear()
+}
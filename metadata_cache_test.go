@@ -2,6 +2,7 @@ package schema
 
 import (
 	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -67,3 +68,85 @@ func TestMetadataCache_CacheReuse(t *testing.T) {
 	// Should return same pointer (cached)
 	assert.Equal(t, structMeta1, structMeta2)
 }
+
+func TestMetadataCache_ClearCache(t *testing.T) {
+	type User struct {
+		Name string `schema:"name"`
+	}
+
+	metadata := NewDefaultMetadata()
+	typ := reflect.TypeOf(User{})
+
+	structMeta1, err := metadata.GetStructMetadata(typ)
+	require.NoError(t, err)
+
+	metadata.ClearCache()
+
+	structMeta2, err := metadata.GetStructMetadata(typ)
+	require.NoError(t, err)
+
+	// Rebuilt metadata is equal but not the same instance
+	assert.Equal(t, structMeta1, structMeta2)
+	assert.NotSame(t, structMeta1, structMeta2)
+}
+
+func TestMetadataCache_ConcurrentAccess(t *testing.T) {
+	type User struct {
+		Name  string `schema:"name"`
+		Email string `schema:"email"`
+	}
+
+	metadata := NewDefaultMetadata()
+	typ := reflect.TypeOf(User{})
+
+	const goroutines = 64
+	results := make([]*StructMetadata, goroutines)
+	var wg sync.WaitGroup
+	for i := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			structMeta, err := metadata.GetStructMetadata(typ)
+			assert.NoError(t, err)
+			results[i] = structMeta
+		}()
+	}
+	wg.Wait()
+
+	// Every goroutine observes the single stored instance
+	for _, structMeta := range results {
+		assert.Same(t, results[0], structMeta)
+	}
+}
+
+type benchmarkCacheStruct struct {
+	ID        int    `schema:"id"`
+	Name      string `schema:"name"`
+	Email     string `schema:"email"`
+	CreatedAt string `schema:"created_at"`
+	UpdatedAt string `schema:"updated_at"`
+}
+
+func BenchmarkMetadataCache_Cached(b *testing.B) {
+	metadata := NewDefaultMetadata()
+	typ := reflect.TypeOf(benchmarkCacheStruct{})
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := metadata.GetStructMetadata(typ); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMetadataCache_Uncached(b *testing.B) {
+	builder := newMetadataBuilder(NewDefaultTagParserRegistry())
+	typ := reflect.TypeOf(benchmarkCacheStruct{})
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := builder.buildStructMetadata(typ); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
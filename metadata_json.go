@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"encoding/json"
+)
+
+// structMetadataJSON is the stable JSON shape of StructMetadata.
+type structMetadataJSON struct {
+	Type    string              `json:"type"`
+	Name    string              `json:"name"`
+	PkgPath string              `json:"pkg_path"`
+	Fields  []fieldMetadataJSON `json:"fields"`
+}
+
+// fieldMetadataJSON is the stable JSON shape of FieldMetadata.
+type fieldMetadataJSON struct {
+	Name      string   `json:"name"`
+	Column    string   `json:"column"`
+	Type      string   `json:"type"`
+	Kind      string   `json:"kind"`
+	IndexPath []int    `json:"index_path"`
+	Flags     []string `json:"flags"`
+}
+
+// MarshalJSON implements json.Marshaler. Types are rendered by name since reflect.Type is not
+// marshalable, and fields are listed in declaration order:
+//
+//	{"type":"models.User","name":"User","pkg_path":"github.com/acme/models","fields":[{"name":"ID","column":"id","type":"int","kind":"int","index_path":[0],"flags":["pk"]}]}
+//
+// The flag names are the same as in String. Name and pkg_path are empty for anonymous structs.
+// Nested metadata is not included.
+func (m *StructMetadata) MarshalJSON() ([]byte, error) {
+	out := structMetadataJSON{
+		Type:    typeString(m),
+		Name:    m.Name,
+		PkgPath: m.PkgPath,
+		Fields:  make([]fieldMetadataJSON, len(m.Fields)),
+	}
+	for i := range m.Fields {
+		out.Fields[i] = m.Fields[i].toJSON()
+	}
+
+	return json.Marshal(out)
+}
+
+// MarshalJSON implements json.Marshaler with the field shape described on StructMetadata.MarshalJSON.
+// It has a value receiver so that FieldMetadata values returned by FieldByName marshal the same way.
+func (f FieldMetadata) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.toJSON())
+}
+
+// toJSON converts the field to its JSON shape. Slices are never nil so they encode as [].
+func (f *FieldMetadata) toJSON() fieldMetadataJSON {
+	out := fieldMetadataJSON{
+		Name:      f.StructFieldName,
+		Column:    f.Column,
+		Type:      "<nil>",
+		Kind:      "invalid",
+		IndexPath: f.IndexPath,
+		Flags:     f.flags(),
+	}
+	if f.Type != nil {
+		out.Type = f.Type.String()
+		out.Kind = f.Type.Kind().String()
+	}
+	if out.IndexPath == nil {
+		out.IndexPath = []int{}
+	}
+	if out.Flags == nil {
+		out.Flags = []string{}
+	}
+
+	return out
+}
@@ -0,0 +1,60 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructMetadata_MarshalJSON(t *testing.T) {
+	structMeta, err := NewDefaultMetadata().GetStructMetadata(reflect.TypeOf(stringUser{}))
+	require.NoError(t, err)
+
+	data, err := json.Marshal(structMeta)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"type": "schema.stringUser",
+		"name": "stringUser",
+		"pkg_path": "github.com/talav/schema",
+		"fields": [
+			{"name": "ID", "column": "id", "type": "int64", "kind": "int64", "index_path": [0, 0], "flags": ["pk"]},
+			{"name": "Email", "column": "email", "type": "string", "kind": "string", "index_path": [1], "flags": ["required"]},
+			{"name": "Nickname", "column": "nickname", "type": "*string", "kind": "ptr", "index_path": [2], "flags": ["pointer"]},
+			{"name": "Tags", "column": "tags", "type": "[]string", "kind": "slice", "index_path": [3], "flags": ["slice"]},
+			{"name": "Labels", "column": "labels", "type": "map[string]string", "kind": "map", "index_path": [4], "flags": ["map"]},
+			{"name": "Secret", "column": "", "type": "string", "kind": "string", "index_path": [5], "flags": ["ignored"]}
+		]
+	}`, string(data))
+}
+
+func TestFieldMetadata_MarshalJSON(t *testing.T) {
+	t.Run("value and pointer marshal alike", func(t *testing.T) {
+		field := FieldMetadata{
+			StructFieldName: "Name",
+			Column:          "name",
+			Type:            reflect.TypeOf(""),
+			IndexPath:       []int{1},
+			Exported:        true,
+		}
+		expected := `{"name":"Name","column":"name","type":"string","kind":"string","index_path":[1],"flags":[]}`
+
+		fromValue, err := json.Marshal(field)
+		require.NoError(t, err)
+		assert.JSONEq(t, expected, string(fromValue))
+
+		fromPointer, err := json.Marshal(&field)
+		require.NoError(t, err)
+		assert.JSONEq(t, expected, string(fromPointer))
+	})
+
+	t.Run("zero field", func(t *testing.T) {
+		data, err := json.Marshal(FieldMetadata{})
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `{"name":"","column":"","type":"<nil>","kind":"invalid","index_path":[],"flags":["unexported"]}`, string(data))
+	})
+}
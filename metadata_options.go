@@ -0,0 +1,331 @@
+package schema
+
+import "reflect"
+
+// MetadataOption configures how struct metadata is built.
+type MetadataOption func(b *metadataBuilder)
+
+// WithTagKey sets the struct tag key parsed into FieldMetadata.Tag (default "schema").
+// Use it to map fields by "db", "json" or any custom tag.
+// If tagKey is empty, the option is ignored.
+func WithTagKey(tagKey string) MetadataOption {
+	return func(b *metadataBuilder) {
+		if tagKey == "" {
+			return
+		}
+		b.tagKey = tagKey
+	}
+}
+
+// WithTagFallback sets tag keys consulted, in order, after the primary tag key (see WithTagKey),
+// e.g. WithTagFallback("json") to reuse json names while migrating to schema tags.
+//
+// Precedence, with the primary key always first:
+//   - the field is ignored if the primary key is "-", or if a fallback key is "-" and no earlier key
+//     is present on the field (db:"password_hash" json:"-" keeps the field as password_hash);
+//   - Tag.Name is the first non-empty name, falling back to the naming strategy when none has one;
+//   - Tag.Options come from the first key present on the field.
+//
+// Empty keys are skipped.
+func WithTagFallback(keys ...string) MetadataOption {
+	return func(b *metadataBuilder) {
+		for _, key := range keys {
+			if key == "" {
+				continue
+			}
+			b.fallbackKeys = append(b.fallbackKeys, key)
+		}
+	}
+}
+
+// WithNamingStrategy sets the strategy used to derive column names for fields
+// without an explicit tag name (default SnakeCaseNaming).
+// If strategy is nil, the option is ignored.
+func WithNamingStrategy(strategy NamingStrategy) MetadataOption {
+	return func(b *metadataBuilder) {
+		if strategy == nil {
+			return
+		}
+		b.naming = strategy
+	}
+}
+
+// WithNestedMetadata enables recursive parsing of struct types reachable from fields
+// (struct, pointer-to-struct, and slice or array of structs) into FieldMetadata.ElemMetadata.
+// It is opt-in. Self-referential types are supported: a reference to a type that is already being
+// parsed resolves to that type's metadata instance, so Next in type Node struct{ Next *Node }
+// has ElemMetadata pointing back to the Node metadata.
+func WithNestedMetadata() MetadataOption {
+	return func(b *metadataBuilder) {
+		b.nested = true
+	}
+}
+
+// WithEmbeddedPrefix controls whether columns of fields promoted from embedded structs are prefixed
+// with the embedded field's name, converted by the naming strategy: an embedded Address yields
+// address_city and address_zip. The child's own column is resolved first (tag name or naming strategy),
+// so the prefix is applied on top. Prefixes of nested embeddings accumulate.
+// An explicit tag option on the embedded field, such as schema:",prefix=addr_", always takes precedence.
+// Named struct fields flattened with the inline option are prefixed the same way.
+// Without this option (or with enabled set to false) promoted columns are not prefixed.
+func WithEmbeddedPrefix(enabled bool) MetadataOption {
+	return func(b *metadataBuilder) {
+		b.prefixed = enabled
+	}
+}
+
+// WithCaseInsensitiveColumns makes StructMetadata.FieldByColumn fall back to a case-insensitive match
+// (e.g. "USER_ID" finds user_id) when no column matches exactly, for databases that change the case
+// of returned column names. Building fails if two columns are equal ignoring case.
+func WithCaseInsensitiveColumns() MetadataOption {
+	return func(b *metadataBuilder) {
+		b.caseInsensitive = true
+	}
+}
+
+// WithFieldFilter parses only the fields for which keep returns true, e.g. to build a projection
+// that skips fields tagged "internal". The filter runs after embedded structs are flattened and
+// shadowing is resolved, so it sees promoted fields (never the embedded struct field itself) and
+// the remaining fields keep their IndexPath into the original struct. Nested metadata
+// (see WithNestedMetadata) is filtered too. If keep is nil, the option is ignored.
+func WithFieldFilter(keep func(field reflect.StructField) bool) MetadataOption {
+	return func(b *metadataBuilder) {
+		if keep == nil {
+			return
+		}
+		b.filter = keep
+	}
+}
+
+// WithKnownOptions registers additional tag option keys (bare flags or "key=value" keys) accepted
+// in strict tag mode, on top of the built-in ones (location, style, explode, primaryKey, pk, default,
+// required, omitempty, prefix, readonly, ->, inline, squash, alias, order, transient).
+// Empty options are skipped.
+func WithKnownOptions(options ...string) MetadataOption {
+	return func(b *metadataBuilder) {
+		for _, option := range options {
+			if option == "" {
+				continue
+			}
+			b.knownOptions[option] = true
+		}
+	}
+}
+
+// WithStrictTags makes parsing fail when a tag contains an option that is not known (see WithKnownOptions),
+// catching typos such as "primarykey" for "primaryKey". Each offending option is reported as a *FieldError
+// with rule ErrUnknownOption. Without it, unknown options are kept in FieldMetadata.Options and Flags
+// and otherwise ignored.
+func WithStrictTags() MetadataOption {
+	return func(b *metadataBuilder) {
+		b.strictTags = true
+	}
+}
+
+// WithRequireTag makes parsing fail for every exported, non-ignored field without a tag under the
+// tag key (see WithTagKey), catching fields that would silently be named by the naming strategy.
+// A tag under a fallback key (see WithTagFallback) does not satisfy it. Embedded fields and unexported
+// fields are exempt. Each offending field is reported as a *FieldError with rule ErrMissingTag.
+func WithRequireTag() MetadataOption {
+	return func(b *metadataBuilder) {
+		b.requireTag = true
+	}
+}
+
+// WithFieldHook registers fn to post-process each field during parsing, for example to fill Extra or
+// override flags from external configuration. It runs once the field set is final, after tag parsing,
+// column resolution (naming strategy, embedded prefixes, WithColumnOverrides) and WithFieldFilter,
+// and before the lookup indexes are built, so changes to Column are indexed. Hooks run in
+// registration order, on promoted and nested fields too. An error aborts the parse, wrapped with the
+// field name. A nil fn is ignored.
+func WithFieldHook(fn func(field *FieldMetadata) error) MetadataOption {
+	return func(b *metadataBuilder) {
+		if fn == nil {
+			return
+		}
+		b.fieldHooks = append(b.fieldHooks, fn)
+	}
+}
+
+// WithCollectWarnings makes parsing record suspicious but non-fatal conditions, available from
+// StructMetadata.Warnings: an exported field without a tag under the tag key (unless WithRequireTag
+// makes that an error) and a tag option that is not known (unless WithStrictTags does). Parsing still
+// succeeds, so linters can surface the advice while runtime code uses the same metadata.
+func WithCollectWarnings() MetadataOption {
+	return func(b *metadataBuilder) {
+		b.warnings = true
+	}
+}
+
+// WithColumnValidator registers a policy check for column names, e.g. rejecting names longer than
+// 63 bytes (the Postgres identifier limit). It is called by StructMetadata.Validate for each field's
+// final column name, after tag resolution, the naming strategy and embedded prefixes; each rejection
+// is reported as a *FieldError with rule ErrInvalidColumn. If validate is nil, the option is ignored.
+func WithColumnValidator(validate func(name string) error) MetadataOption {
+	return func(b *metadataBuilder) {
+		if validate == nil {
+			return
+		}
+		b.columnValidator = validate
+	}
+}
+
+// WithColumnUniquenessFold makes StructMetadata.Validate also reject columns that differ only by case,
+// such as userName and username, for databases that fold identifier case. Each collision is reported
+// as a *FieldError with rule ErrDuplicateColumn naming both fields and both columns. Unlike
+// WithCaseInsensitiveColumns it does not change lookups, and parsing itself does not fail.
+func WithColumnUniquenessFold() MetadataOption {
+	return func(b *metadataBuilder) {
+		b.foldColumns = true
+	}
+}
+
+// WithImmutable marks parsed metadata, including nested metadata, as read-only so the cached instance
+// can be handed to many goroutines: Reindex returns ErrImmutableMetadata and SetExtra panics.
+// Fields stays an exported slice, so direct writes are not prevented; callers that need to change
+// metadata must Clone it first, which yields a mutable copy (see StructMetadata.Clone).
+func WithImmutable() MetadataOption {
+	return func(b *metadataBuilder) {
+		b.immutable = true
+	}
+}
+
+// ConflictPolicy selects how parsing handles fields promoted from different embedded structs that
+// resolve to the same column (see WithConflictPolicy).
+type ConflictPolicy string
+
+const (
+	// ConflictError makes parsing fail with an ErrDuplicateColumn *FieldError per conflicting field.
+	// It is the default.
+	ConflictError ConflictPolicy = "error"
+	// ConflictKeepFirst keeps the conflicting field declared first and drops the others.
+	ConflictKeepFirst ConflictPolicy = "keep_first"
+	// ConflictKeepLast keeps the conflicting field declared last and drops the others.
+	ConflictKeepLast ConflictPolicy = "keep_last"
+)
+
+// WithConflictPolicy sets how column conflicts between embedded structs are resolved: when fields
+// promoted from two embedded structs at the same depth, e.g. Name in both, resolve to the same column
+// after the naming strategy and embedded prefixes, parsing fails by default (ConflictError) rather
+// than silently dropping them. Shallower fields still shadow deeper ones by Go's rules, and duplicate
+// columns declared directly on the struct are left to Validate. An empty policy is ignored.
+func WithConflictPolicy(policy ConflictPolicy) MetadataOption {
+	return func(b *metadataBuilder) {
+		if policy == "" {
+			return
+		}
+		b.conflictPolicy = policy
+	}
+}
+
+// WithMaxEmbedDepth limits flattening of embedded structs to n levels: with 0 nothing is flattened,
+// with 1 only structs embedded directly in the root are, and so on. Embedded structs at the limit are
+// kept as single fields with Embedded and IsStruct set. A negative n means unlimited, the default.
+func WithMaxEmbedDepth(n int) MetadataOption {
+	return func(b *metadataBuilder) {
+		b.maxEmbedDepth = n
+	}
+}
+
+// WithTypeDecoder registers a conversion used by FromMap for fields of type t, or pointers to t,
+// consulted before the default assignable/convertible logic: for example parsing an RFC3339 string
+// into time.Time. fn receives the raw map value and returns a value assignable or convertible to t.
+// Nil map values bypass the decoder and set the zero value. Errors returned by fn are wrapped with
+// the field name. Registering t again replaces the previous decoder. If t or fn is nil, the option is ignored.
+func WithTypeDecoder(t reflect.Type, fn func(any) (any, error)) MetadataOption {
+	return func(b *metadataBuilder) {
+		if t == nil || fn == nil {
+			return
+		}
+		if b.typeDecoders == nil {
+			b.typeDecoders = make(map[reflect.Type]typeConverter)
+		}
+		b.typeDecoders[t] = fn
+	}
+}
+
+// WithTypeEncoder registers a conversion used by ToMap for field values of type t, for example
+// formatting time.Time as an RFC3339 string. It receives the dereferenced field value, so it also
+// applies to fields of type *t; nil pointers still produce a nil entry. Errors returned by fn are
+// wrapped with the field name. Registering t again replaces the previous encoder.
+// If t or fn is nil, the option is ignored.
+func WithTypeEncoder(t reflect.Type, fn func(any) (any, error)) MetadataOption {
+	return func(b *metadataBuilder) {
+		if t == nil || fn == nil {
+			return
+		}
+		if b.typeEncoders == nil {
+			b.typeEncoders = make(map[reflect.Type]typeConverter)
+		}
+		b.typeEncoders[t] = fn
+	}
+}
+
+// WithColumnOverrides sets the columns of fields of typ by Go struct field name, without touching struct
+// tags (e.g. for structs from a third-party package mapped onto a legacy schema). Promoted fields are
+// matched by their own name, so "City" overrides a City promoted from an embedded Address.
+//
+// Column precedence, highest first:
+//   - an override from WithColumnOverrides, used verbatim (embedded prefixes are not applied);
+//   - the tag name, from the primary tag key or a fallback key (see WithTagFallback);
+//   - the name derived by the naming strategy, with any embedded prefix.
+//
+// Overrides apply only to typ (pointers to it are treated the same), whether it is parsed directly or
+// as a nested element type; other structs parsed by the Metadata are unaffected. Parsing typ fails if
+// an override names none of its fields, so typos are caught. Ignored fields keep no column. Repeated
+// calls for the same type merge, and entries with an empty field or column name are skipped.
+// If typ is nil, the option is ignored.
+func WithColumnOverrides(typ reflect.Type, overrides map[string]string) MetadataOption {
+	return func(b *metadataBuilder) {
+		if typ == nil {
+			return
+		}
+		typ = derefType(typ)
+		for field, column := range overrides {
+			if field == "" || column == "" {
+				continue
+			}
+			if b.overrides == nil {
+				b.overrides = make(map[reflect.Type]map[string]string)
+			}
+			if b.overrides[typ] == nil {
+				b.overrides[typ] = make(map[string]string, len(overrides))
+			}
+			b.overrides[typ][field] = column
+		}
+	}
+}
+
+// WithScalarTypes registers struct types that are treated as leaf values (e.g. uuid.UUID, decimal.Decimal),
+// in addition to the pre-registered time.Time. Fields of these types, or pointers to them, are marked
+// IsScalar and are never flattened or parsed into nested metadata. Nil types are skipped.
+func WithScalarTypes(types ...reflect.Type) MetadataOption {
+	return func(b *metadataBuilder) {
+		for _, typ := range types {
+			if typ == nil {
+				continue
+			}
+			b.scalarTypes[derefType(typ)] = true
+		}
+	}
+}
+
+// WithIncludeUnexported includes unexported fields, which are skipped by default.
+// They are marked with Exported set to false since they cannot be set via reflection,
+// and are skipped by ToMap and FromMap. Intended for tooling such as debugging dumps.
+func WithIncludeUnexported() MetadataOption {
+	return func(b *metadataBuilder) {
+		b.unexported = true
+	}
+}
+
+// WithSQLTypeMapper sets the mapper used by StructMetadata.Columns (default DefaultSQLTypeMapper).
+// If mapper is nil, the option is ignored.
+func WithSQLTypeMapper(mapper SQLTypeMapper) MetadataOption {
+	return func(b *metadataBuilder) {
+		if mapper == nil {
+			return
+		}
+		b.sqlTypes = mapper
+	}
+}
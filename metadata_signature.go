@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// Signature returns a stable hex-encoded SHA-256 hash of the metadata, for detecting schema changes
+// across process restarts (e.g. invalidating persisted caches or versioning configuration).
+// It covers the struct type and, per field in declaration order, the name, column, type, kind,
+// index path, the flags listed by String, the default value, aliases and tag options. Maps are
+// hashed in sorted key order, so the result is deterministic; TagMetadata, Extra and nested
+// metadata are not included.
+func (m *StructMetadata) Signature() string {
+	h := sha256.New()
+	writeSignatureString(h, typeString(m))
+	writeSignatureString(h, strconv.Itoa(len(m.Fields)))
+	for i := range m.Fields {
+		m.Fields[i].writeSignature(h)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeSignature writes the canonical form of the field to h.
+func (f *FieldMetadata) writeSignature(h hash.Hash) {
+	out := f.toJSON()
+	writeSignatureString(h, out.Name)
+	writeSignatureString(h, out.Column)
+	writeSignatureString(h, out.Type)
+	writeSignatureString(h, out.Kind)
+	indexPath := make([]string, len(out.IndexPath))
+	for i, idx := range out.IndexPath {
+		indexPath[i] = strconv.Itoa(idx)
+	}
+	writeSignatureList(h, indexPath)
+	writeSignatureList(h, out.Flags)
+	writeSignatureString(h, f.Default)
+	writeSignatureList(h, f.Aliases)
+
+	keys := slices.Sorted(maps.Keys(f.Options))
+	options := make([]string, 0, len(keys)+len(f.Flags))
+	for _, key := range keys {
+		options = append(options, key+"="+f.Options[key])
+	}
+	options = append(options, slices.Sorted(maps.Keys(f.Flags))...)
+	writeSignatureList(h, options)
+}
+
+// writeSignatureList writes the length of values followed by each value.
+func writeSignatureList(h hash.Hash, values []string) {
+	writeSignatureString(h, strconv.Itoa(len(values)))
+	for _, value := range values {
+		writeSignatureString(h, value)
+	}
+}
+
+// writeSignatureString writes s length-prefixed, so adjacent values cannot run into each other.
+func writeSignatureString(h hash.Hash, s string) {
+	_, _ = h.Write([]byte(strconv.Itoa(len(s))))
+	_, _ = h.Write([]byte{':'})
+	_, _ = h.Write([]byte(s))
+}
@@ -0,0 +1,60 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructMetadata_Signature(t *testing.T) {
+	type UserV1 struct {
+		ID    int    `schema:"id,pk"`
+		Email string `schema:"email,omitempty,default=none,alias=mail"`
+	}
+
+	first, err := NewDefaultMetadata().Parse(UserV1{})
+	require.NoError(t, err)
+	second, err := NewDefaultMetadata().Parse(&UserV1{})
+	require.NoError(t, err)
+
+	signature := first.Signature()
+	assert.Len(t, signature, 64)
+	assert.Equal(t, signature, first.Signature(), "repeated calls")
+	assert.Equal(t, signature, second.Signature(), "separate caches and pointer roots")
+	assert.Equal(t, signature, first.Clone().Signature())
+
+	changed := func(name string, mutate func(m *StructMetadata)) {
+		t.Helper()
+		clone := first.Clone()
+		mutate(clone)
+		assert.NotEqual(t, signature, clone.Signature(), name)
+	}
+	changed("column", func(m *StructMetadata) { m.Fields[1].Column = "mail" })
+	changed("flag", func(m *StructMetadata) { m.Fields[0].IsPrimaryKey = false })
+	changed("default", func(m *StructMetadata) { m.Fields[1].Default = "" })
+	changed("alias", func(m *StructMetadata) { m.Fields[1].Aliases = nil })
+	changed("option", func(m *StructMetadata) { m.Fields[1].Options["default"] = "other" })
+	changed("field order", func(m *StructMetadata) { m.Fields[0], m.Fields[1] = m.Fields[1], m.Fields[0] })
+	changed("field removed", func(m *StructMetadata) { m.Fields = m.Fields[:1] })
+
+	t.Run("tag change", func(t *testing.T) {
+		type UserV1 struct {
+			ID    int    `schema:"id,pk"`
+			Email string `schema:"email_address,omitempty,default=none,alias=mail"`
+		}
+		renamed, err := NewDefaultMetadata().Parse(UserV1{})
+		require.NoError(t, err)
+		assert.NotEqual(t, signature, renamed.Signature())
+	})
+
+	t.Run("type change", func(t *testing.T) {
+		type UserV1 struct {
+			ID    int64  `schema:"id,pk"`
+			Email string `schema:"email,omitempty,default=none,alias=mail"`
+		}
+		retyped, err := NewDefaultMetadata().Parse(UserV1{})
+		require.NoError(t, err)
+		assert.NotEqual(t, signature, retyped.Signature())
+	})
+}
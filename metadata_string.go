@@ -0,0 +1,131 @@
+package schema
+
+import (
+	"strconv"
+	"strings"
+)
+
+// String returns a multi-line, deterministic dump of the struct metadata: a header with the struct type,
+// qualified by its package path, and field count, e.g. "StructMetadata(github.com/acme/models.User, 5 fields)",
+// followed by one line per field in declaration order. Anonymous struct types are shown as
+// reflect.Type.String() renders them. Intended for debugging and golden-file tests.
+func (m *StructMetadata) String() string {
+	var sb strings.Builder
+	sb.Grow(64 * (len(m.Fields) + 1))
+	sb.WriteString("StructMetadata(")
+	if m.Name != "" && m.PkgPath != "" {
+		sb.WriteString(m.PkgPath)
+		sb.WriteByte('.')
+		sb.WriteString(m.Name)
+	} else {
+		sb.WriteString(typeString(m))
+	}
+	sb.WriteString(", ")
+	sb.WriteString(strconv.Itoa(len(m.Fields)))
+	if len(m.Fields) == 1 {
+		sb.WriteString(" field)")
+	} else {
+		sb.WriteString(" fields)")
+	}
+	for i := range m.Fields {
+		sb.WriteString("\n  ")
+		m.Fields[i].writeTo(&sb)
+	}
+
+	return sb.String()
+}
+
+// String returns a single-line description of the field, e.g.
+// "ID column=id type=int index=[0] source=schema flags=pk,required". The source (see SourceTag) is
+// left out when empty. It has a value receiver so that FieldMetadata values returned by FieldByName
+// print the same way with fmt.
+func (f FieldMetadata) String() string {
+	var sb strings.Builder
+	f.writeTo(&sb)
+
+	return sb.String()
+}
+
+// writeTo writes the field description to sb.
+func (f *FieldMetadata) writeTo(sb *strings.Builder) {
+	sb.WriteString(f.StructFieldName)
+	sb.WriteString(" column=")
+	sb.WriteString(f.Column)
+	sb.WriteString(" type=")
+	if f.Type == nil {
+		sb.WriteString("<nil>")
+	} else {
+		sb.WriteString(f.Type.String())
+	}
+
+	sb.WriteString(" index=[")
+	for i, idx := range f.IndexPath {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(strconv.Itoa(idx))
+	}
+	sb.WriteByte(']')
+
+	if f.SourceTag != "" {
+		sb.WriteString(" source=")
+		sb.WriteString(f.SourceTag)
+	}
+
+	first := true
+	for _, flag := range f.flags() {
+		if first {
+			sb.WriteString(" flags=")
+			first = false
+		} else {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(flag)
+	}
+}
+
+// flags returns the names of the boolean properties set on the field, in a fixed order.
+func (f *FieldMetadata) flags() []string {
+	candidates := [...]struct {
+		name string
+		set  bool
+	}{
+		{"embedded", f.Embedded},
+		{"unexported", !f.Exported},
+		{"pointer", f.IsPointer},
+		{"slice", f.IsSlice},
+		{"array", f.IsArray},
+		{"map", f.IsMap},
+		{"struct", f.IsStruct},
+		{"interface", f.IsInterface},
+		{"scalar", f.IsScalar},
+		{"scanner", f.IsScanner},
+		{"valuer", f.IsValuer},
+		{"textmarshaler", f.IsTextMarshaler},
+		{"textunmarshaler", f.IsTextUnmarshaler},
+		{"ignored", f.Ignored},
+		{"pk", f.IsPrimaryKey},
+		{"readonly", f.ReadOnly},
+		{"required", f.Required},
+		{"omitempty", f.OmitEmpty},
+		{"transient", f.Transient},
+	}
+
+	var flags []string
+	for _, c := range candidates {
+		if c.set {
+			flags = append(flags, c.name)
+		}
+	}
+
+	return flags
+}
+
+// typeString returns the struct type name, or "<nil>" when no type is set.
+func typeString(m *StructMetadata) string {
+	if m.Type == nil {
+		return "<nil>"
+	}
+
+	return m.Type.String()
+}
@@ -0,0 +1,92 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stringBase struct {
+	ID int64 `schema:"id,pk"`
+}
+
+type stringUser struct {
+	stringBase
+	Email    string            `schema:"email,required"`
+	Nickname *string           `schema:"nickname"`
+	Tags     []string          `schema:"tags"`
+	Labels   map[string]string `schema:"labels"`
+	Secret   string            `schema:"-"`
+}
+
+func TestStructMetadata_String(t *testing.T) {
+	metadata := NewDefaultMetadata()
+
+	structMeta, err := metadata.GetStructMetadata(reflect.TypeOf(stringUser{}))
+	require.NoError(t, err)
+
+	expected := "StructMetadata(github.com/talav/schema.stringUser, 6 fields)\n" +
+		"  ID column=id type=int64 index=[0 0] source=schema flags=pk\n" +
+		"  Email column=email type=string index=[1] source=schema flags=required\n" +
+		"  Nickname column=nickname type=*string index=[2] source=schema flags=pointer\n" +
+		"  Tags column=tags type=[]string index=[3] source=schema flags=slice\n" +
+		"  Labels column=labels type=map[string]string index=[4] source=schema flags=map\n" +
+		"  Secret column= type=string index=[5] flags=ignored"
+
+	assert.Equal(t, expected, structMeta.String())
+	assert.Equal(t, expected, structMeta.String(), "output must be deterministic")
+}
+
+func TestFieldMetadata_String(t *testing.T) {
+	t.Run("populated field", func(t *testing.T) {
+		field := &FieldMetadata{
+			StructFieldName: "ID",
+			Column:          "id",
+			Type:            reflect.TypeOf(0),
+			IndexPath:       []int{0},
+			Exported:        true,
+			IsPrimaryKey:    true,
+			Required:        true,
+		}
+
+		assert.Equal(t, "ID column=id type=int index=[0] flags=pk,required", field.String())
+
+		field.SourceTag = "json"
+		assert.Equal(t, "ID column=id type=int index=[0] source=json flags=pk,required", field.String())
+	})
+
+	t.Run("zero field", func(t *testing.T) {
+		field := &FieldMetadata{}
+
+		assert.Equal(t, " column= type=<nil> index=[] flags=unexported", field.String())
+	})
+
+	t.Run("values format with fmt", func(t *testing.T) {
+		type user struct {
+			ID int `schema:"id,pk"`
+		}
+		structMeta, err := NewDefaultMetadata().Parse(user{})
+		require.NoError(t, err)
+
+		assert.Equal(t, "ID column=id type=int index=[0] source=schema flags=pk", fmt.Sprint(structMeta.MustFieldByName("ID")))
+	})
+}
+
+func TestStructMetadata_String_Header(t *testing.T) {
+	type single struct {
+		ID int
+	}
+
+	structMeta, err := NewDefaultMetadata().Parse(single{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "StructMetadata(github.com/talav/schema.single, 1 field)\n  ID column=id type=int index=[0] source=naming", structMeta.String())
+	assert.Equal(t, "StructMetadata(<nil>, 0 fields)", (&StructMetadata{}).String())
+
+	anonymous, err := NewDefaultMetadata().Parse(struct{ ID int }{})
+	require.NoError(t, err)
+	assert.Equal(t, "StructMetadata(struct { ID int }, 1 field)\n  ID column=id type=int index=[0] source=naming", anonymous.String())
+}
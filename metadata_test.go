@@ -1,7 +1,9 @@
 package schema
 
 import (
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -30,6 +32,7 @@ func TestValidateField(t *testing.T) {
 		err := validateField(field)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "structFieldName cannot be empty")
+		assert.Equal(t, []ValidationRule{ErrEmptyName}, fieldErrorRules(err))
 	})
 
 	t.Run("nil Type", func(t *testing.T) {
@@ -41,6 +44,7 @@ func TestValidateField(t *testing.T) {
 		err := validateField(field)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "type cannot be nil")
+		assert.Equal(t, []ValidationRule{ErrNilType}, fieldErrorRules(err))
 	})
 
 	t.Run("negative Index", func(t *testing.T) {
@@ -52,6 +56,7 @@ func TestValidateField(t *testing.T) {
 		err := validateField(field)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "index must be non-negative")
+		assert.Equal(t, []ValidationRule{ErrNegativeIndex}, fieldErrorRules(err))
 	})
 
 	t.Run("multiple errors", func(t *testing.T) {
@@ -65,5 +70,1129 @@ func TestValidateField(t *testing.T) {
 		assert.Contains(t, err.Error(), "structFieldName cannot be empty")
 		assert.Contains(t, err.Error(), "type cannot be nil")
 		assert.Contains(t, err.Error(), "index must be non-negative")
+		assert.Equal(t, []ValidationRule{ErrEmptyName, ErrNilType, ErrNegativeIndex}, fieldErrorRules(err))
 	})
 }
+
+func TestStructMetadata_FieldByName(t *testing.T) {
+	type User struct {
+		Name  string
+		Email string
+	}
+
+	typ := reflect.TypeOf(User{})
+	structMeta, err := NewStructMetadata(typ, []FieldMetadata{
+		{StructFieldName: "Name", Type: reflect.TypeOf(""), Index: 0},
+		{StructFieldName: "Email", Type: reflect.TypeOf(""), Index: 1},
+	})
+	require.NoError(t, err)
+
+	t.Run("existing field", func(t *testing.T) {
+		field, ok := structMeta.FieldByName("Email")
+		require.True(t, ok)
+		assert.Equal(t, "Email", field.StructFieldName)
+		assert.Equal(t, 1, field.Index)
+	})
+
+	t.Run("missing field", func(t *testing.T) {
+		field, ok := structMeta.FieldByName("Age")
+		assert.False(t, ok)
+		assert.Equal(t, FieldMetadata{}, field)
+	})
+
+	t.Run("case-sensitive", func(t *testing.T) {
+		_, ok := structMeta.FieldByName("email")
+		assert.False(t, ok)
+	})
+
+	t.Run("must variant", func(t *testing.T) {
+		assert.Equal(t, "Name", structMeta.MustFieldByName("Name").StructFieldName)
+		assert.PanicsWithValue(t, `schema: field "Age" not found in schema.User`, func() {
+			structMeta.MustFieldByName("Age")
+		})
+	})
+}
+
+func TestStructMetadata_FieldByColumn(t *testing.T) {
+	type User struct {
+		UserID  int `schema:"id"`
+		Name    string
+		Ignored string `schema:"-"`
+	}
+
+	structMeta, err := NewDefaultMetadata().GetStructMetadata(reflect.TypeOf(User{}))
+	require.NoError(t, err)
+
+	field, ok := structMeta.FieldByColumn("id")
+	require.True(t, ok)
+	assert.Equal(t, "UserID", field.StructFieldName)
+
+	field, ok = structMeta.FieldByColumn("name")
+	require.True(t, ok)
+	assert.Equal(t, "Name", field.StructFieldName)
+
+	_, ok = structMeta.FieldByColumn("Name")
+	assert.False(t, ok)
+
+	_, ok = structMeta.FieldByColumn("")
+	assert.False(t, ok, "ignored fields have no column")
+}
+
+func TestStructMetadata_FieldByPath(t *testing.T) {
+	type Geo struct {
+		Lat float64 `schema:"lat"`
+	}
+	type Address struct {
+		City string `schema:"city"`
+		Geo  Geo    `schema:"geo"`
+	}
+	type Audit struct {
+		CreatedBy string `schema:"created_by"`
+	}
+	type User struct {
+		ID       int     `schema:"id"`
+		Home     Address `schema:",inline"`
+		Work     Address `schema:"work"`
+		Shipping *Address
+		Audit
+	}
+
+	structMeta, err := NewDefaultMetadata(WithNestedMetadata(), WithEmbeddedPrefix(true)).Parse(User{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		path       string
+		wantColumn string
+		wantIndex  []int
+	}{
+		{path: "ID", wantColumn: "id", wantIndex: []int{0}},
+		{path: "Home.City", wantColumn: "home_city", wantIndex: []int{1, 0}},
+		{path: "Home.Geo", wantColumn: "home_geo", wantIndex: []int{1, 1}},
+		{path: "Home.Geo.Lat", wantColumn: "lat", wantIndex: []int{0}},
+		{path: "Work", wantColumn: "work", wantIndex: []int{2}},
+		{path: "Work.City", wantColumn: "city", wantIndex: []int{0}},
+		{path: "Work.Geo.Lat", wantColumn: "lat", wantIndex: []int{0}},
+		{path: "Shipping.City", wantColumn: "city", wantIndex: []int{0}},
+		{path: "Audit.CreatedBy", wantColumn: "audit_created_by", wantIndex: []int{4, 0}},
+		{path: "CreatedBy", wantColumn: "audit_created_by", wantIndex: []int{4, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			field, ok := structMeta.FieldByPath(tt.path)
+			require.True(t, ok)
+			assert.Equal(t, tt.wantColumn, field.Column)
+			assert.Equal(t, tt.wantIndex, field.IndexPath)
+		})
+	}
+
+	for _, path := range []string{"", ".", "Home", "Home.", "Home.Zip", "Work.Zip", "ID.Value", "Work.Geo.Lat.X", "home_city", "Audit"} {
+		_, ok := structMeta.FieldByPath(path)
+		assert.False(t, ok, path)
+	}
+
+	t.Run("without nested metadata", func(t *testing.T) {
+		flat, err := NewDefaultMetadata().Parse(User{})
+		require.NoError(t, err)
+
+		_, ok := flat.FieldByPath("Work.City")
+		assert.False(t, ok)
+		field, ok := flat.FieldByPath("Home.City")
+		require.True(t, ok)
+		assert.Equal(t, []int{1, 0}, field.IndexPath)
+	})
+}
+
+func TestStructMetadata_LinearAndIndexedLookups(t *testing.T) {
+	for _, size := range []int{3, indexedLookupMinFields - 1, indexedLookupMinFields, 20} {
+		t.Run(fmt.Sprintf("fields=%d", size), func(t *testing.T) {
+			fields := syntheticFields(size)
+			// A duplicate column resolves to the first field in both modes
+			fields[size-1].Column = fields[0].Column
+
+			structMeta, err := NewStructMetadata(reflect.TypeOf(struct{}{}), fields)
+			require.NoError(t, err)
+			assert.Equal(t, size >= indexedLookupMinFields, structMeta.fieldsByName != nil)
+
+			for i := range size {
+				field, ok := structMeta.Field(fmt.Sprintf("Field%d", i))
+				require.True(t, ok)
+				assert.Same(t, &structMeta.Fields[i], field)
+			}
+
+			byColumn, ok := structMeta.FieldByColumn("field_0")
+			require.True(t, ok)
+			assert.Equal(t, "Field0", byColumn.StructFieldName)
+
+			_, ok = structMeta.FieldByName("Missing")
+			assert.False(t, ok)
+			_, ok = structMeta.FieldByColumn("")
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestStructMetadata_FieldByColumn_CaseInsensitive(t *testing.T) {
+	type User struct {
+		UserID int    `schema:"user_id"`
+		Name   string `schema:"Name"`
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		structMeta, err := NewDefaultMetadata().GetStructMetadata(reflect.TypeOf(User{}))
+		require.NoError(t, err)
+
+		_, ok := structMeta.FieldByColumn("USER_ID")
+		assert.False(t, ok)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		structMeta, err := NewDefaultMetadata(WithCaseInsensitiveColumns()).GetStructMetadata(reflect.TypeOf(User{}))
+		require.NoError(t, err)
+
+		for col, expected := range map[string]string{
+			"user_id": "UserID",
+			"USER_ID": "UserID",
+			"User_Id": "UserID",
+			"Name":    "Name",
+			"name":    "Name",
+		} {
+			field, ok := structMeta.FieldByColumn(col)
+			require.True(t, ok, col)
+			assert.Equal(t, expected, field.StructFieldName, col)
+		}
+
+		_, ok := structMeta.FieldByColumn("email")
+		assert.False(t, ok)
+	})
+
+	t.Run("columns colliding ignoring case fail to build", func(t *testing.T) {
+		type Conflict struct {
+			Email      string `schema:"email"`
+			EmailUpper string `schema:"EMAIL"`
+		}
+
+		_, err := NewDefaultMetadata(WithCaseInsensitiveColumns()).GetStructMetadata(reflect.TypeOf(Conflict{}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `field "EmailUpper": column "EMAIL" collides case-insensitively with column "email" of field "Email"`)
+		assert.Equal(t, []ValidationRule{ErrDuplicateColumn}, fieldErrorRules(err))
+
+		_, err = NewDefaultMetadata().GetStructMetadata(reflect.TypeOf(Conflict{}))
+		assert.NoError(t, err)
+	})
+}
+
+func TestStructMetadata_PrimaryKeys(t *testing.T) {
+	t.Run("composite key in declaration order", func(t *testing.T) {
+		type Membership struct {
+			OrgID  int `schema:"org_id,primaryKey"`
+			Role   string
+			UserID int `schema:"user_id,pk"`
+		}
+
+		structMeta, err := NewDefaultMetadata().GetStructMetadata(reflect.TypeOf(Membership{}))
+		require.NoError(t, err)
+
+		keys := structMeta.PrimaryKeys()
+		require.Len(t, keys, 2)
+		assert.Equal(t, "OrgID", keys[0].StructFieldName)
+		assert.Equal(t, "UserID", keys[1].StructFieldName)
+		assert.False(t, structMeta.MustFieldByName("Role").IsPrimaryKey)
+
+		// Stable across calls
+		assert.Equal(t, keys, structMeta.PrimaryKeys())
+	})
+
+	t.Run("no primary key", func(t *testing.T) {
+		type Event struct {
+			Name string
+		}
+
+		structMeta, err := NewDefaultMetadata().GetStructMetadata(reflect.TypeOf(Event{}))
+		require.NoError(t, err)
+
+		keys := structMeta.PrimaryKeys()
+		assert.NotNil(t, keys)
+		assert.Empty(t, keys)
+	})
+}
+
+func TestStructMetadata_Validate(t *testing.T) {
+	t.Run("valid struct", func(t *testing.T) {
+		type User struct {
+			ID      int `schema:"id"`
+			Name    string
+			Ignored string `schema:"-"`
+			Other   string `schema:"-"`
+		}
+
+		structMeta, err := NewDefaultMetadata().GetStructMetadata(reflect.TypeOf(User{}))
+		require.NoError(t, err)
+
+		assert.NoError(t, structMeta.Validate())
+	})
+
+	t.Run("duplicate tag names and columns", func(t *testing.T) {
+		type User struct {
+			UserName string
+			Name     string `schema:"user_name"`
+			ID       int    `schema:"id"`
+			AltID    int    `schema:"id"`
+		}
+
+		structMeta, err := NewDefaultMetadata().GetStructMetadata(reflect.TypeOf(User{}))
+		require.NoError(t, err)
+
+		err = structMeta.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `field "Name": duplicate column "user_name", already used by field "UserName"`)
+		assert.Contains(t, err.Error(), `field "AltID": duplicate tag name "id", already used by field "ID"`)
+	})
+
+	t.Run("invalid fields are all reported", func(t *testing.T) {
+		structMeta := &StructMetadata{
+			Fields: []FieldMetadata{
+				{StructFieldName: "Name", Type: nil, Index: 0},
+				{StructFieldName: "Age", Type: reflect.TypeOf(0), Index: -1},
+			},
+		}
+
+		err := structMeta.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `field "Name": type cannot be nil`)
+		assert.Contains(t, err.Error(), `field "Age": index must be non-negative, got -1`)
+	})
+
+	t.Run("column validator", func(t *testing.T) {
+		type Base struct {
+			CreatedAt string
+		}
+		type Account struct {
+			ID                           int    `schema:"id"`
+			Name                         string `schema:"user-name"`
+			VeryLongFieldNameExceedingIt string
+			Secret                       string `schema:"-"`
+			Base                         `schema:",prefix=base$"`
+		}
+
+		var seen []string
+		validator := func(name string) error {
+			seen = append(seen, name)
+			if len(name) > 20 {
+				return fmt.Errorf("longer than 20 bytes")
+			}
+			if strings.ContainsAny(name, "-$") {
+				return fmt.Errorf("contains a forbidden character")
+			}
+
+			return nil
+		}
+
+		structMeta, err := NewDefaultMetadata(WithColumnValidator(validator)).GetStructMetadata(reflect.TypeOf(Account{}))
+		require.NoError(t, err)
+
+		err = structMeta.Validate()
+		require.Error(t, err)
+		assert.Equal(t, []ValidationRule{ErrInvalidColumn, ErrInvalidColumn, ErrInvalidColumn}, fieldErrorRules(err))
+		assert.Contains(t, err.Error(), `field "Name": invalid column "user-name": contains a forbidden character`)
+		assert.Contains(t, err.Error(), `field "VeryLongFieldNameExceedingIt": invalid column "very_long_field_name_exceeding_it": longer than 20 bytes`)
+		assert.Contains(t, err.Error(), `field "CreatedAt": invalid column "base$created_at": contains a forbidden character`)
+
+		// The validator sees final names and skips ignored fields
+		assert.Equal(t, []string{"id", "user-name", "very_long_field_name_exceeding_it", "base$created_at"}, seen)
+	})
+
+	t.Run("column uniqueness fold", func(t *testing.T) {
+		type Account struct {
+			UserName string `schema:"userName"`
+			Username string `schema:"username"`
+			Email    string `schema:"email"`
+			Mail     string `schema:"EMAIL"`
+			Other    string `schema:"email"`
+			Secret   string `schema:"-"`
+		}
+
+		structMeta, err := NewDefaultMetadata().Parse(Account{})
+		require.NoError(t, err)
+		assert.Equal(t, []ValidationRule{ErrDuplicateTagName}, fieldErrorRules(structMeta.Validate()),
+			"without the option only exact duplicates are reported")
+
+		structMeta, err = NewDefaultMetadata(WithColumnUniquenessFold()).Parse(Account{})
+		require.NoError(t, err, "parsing succeeds, the check runs in Validate")
+
+		err = structMeta.Validate()
+		require.Error(t, err)
+		assert.Equal(t, []ValidationRule{ErrDuplicateTagName, ErrDuplicateColumn, ErrDuplicateColumn}, fieldErrorRules(err))
+		assert.Contains(t, err.Error(), `field "Username": column "username" differs only by case from column "userName" of field "UserName"`)
+		assert.Contains(t, err.Error(), `field "Mail": column "EMAIL" differs only by case from column "email" of field "Email"`)
+		assert.Contains(t, err.Error(), `field "Other": duplicate tag name "email", already used by field "Email"`,
+			"exact duplicates are reported once")
+	})
+
+	t.Run("duplicate index paths", func(t *testing.T) {
+		type User struct {
+			ID      int
+			Address struct{ City, Zip string }
+		}
+
+		// Simulates a flattening bug where a promoted field claims the path of another field
+		fields := []FieldMetadata{
+			{StructFieldName: "ID", Type: reflect.TypeOf(0), Index: 0, IndexPath: []int{0}},
+			{StructFieldName: "City", Type: reflect.TypeOf(""), Index: 0, IndexPath: []int{1, 0}, IsNested: true},
+			{StructFieldName: "Zip", Type: reflect.TypeOf(""), Index: 0, IndexPath: []int{1, 0}, IsNested: true},
+			{StructFieldName: "Code", Type: reflect.TypeOf(""), Index: 0},
+		}
+
+		structMeta := &StructMetadata{Type: reflect.TypeOf(User{}), Fields: fields}
+		err := structMeta.Validate()
+		require.Error(t, err)
+		assert.Equal(t, []ValidationRule{ErrDuplicateIndexPath, ErrDuplicateIndexPath}, fieldErrorRules(err))
+		assert.Contains(t, err.Error(), `field "Zip": index path [1 0] already used by field "City"`)
+		assert.Contains(t, err.Error(), `field "Code": index path [0] already used by field "ID"`)
+
+		_, err = NewStructMetadata(reflect.TypeOf(User{}), fields)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `field "Zip": index path [1 0] already used by field "City"`)
+	})
+
+	t.Run("index paths out of bounds", func(t *testing.T) {
+		type Address struct {
+			City string
+		}
+		type User struct {
+			ID int
+			*Address
+		}
+
+		structMeta, err := NewDefaultMetadata().Parse(User{})
+		require.NoError(t, err)
+		require.NoError(t, structMeta.Validate(), "paths through embedded pointers resolve")
+
+		clone := structMeta.Clone()
+		clone.Fields[0].Index, clone.Fields[0].IndexPath = 5, []int{5}
+		clone.Fields[1].Index, clone.Fields[1].IndexPath = 3, []int{1, 3}
+		clone.Fields = append(clone.Fields,
+			FieldMetadata{StructFieldName: "Deep", Type: reflect.TypeOf(""), Index: 0, IndexPath: []int{0, 0}, IsNested: true},
+			FieldMetadata{StructFieldName: "Negative", Type: reflect.TypeOf(""), Index: 0, IndexPath: []int{-1, 0}, IsNested: true},
+			FieldMetadata{StructFieldName: "Flat", Type: reflect.TypeOf(""), Index: 7},
+		)
+
+		err = clone.Validate()
+		require.Error(t, err)
+		assert.Equal(t, []ValidationRule{ErrInvalidIndexPath, ErrInvalidIndexPath, ErrInvalidIndexPath, ErrInvalidIndexPath, ErrInvalidIndexPath},
+			fieldErrorRules(err))
+		assert.Contains(t, err.Error(), `field "ID": index path [5] does not resolve on schema.User: index 5 at position 0 out of range for schema.User with 2 fields`)
+		assert.Contains(t, err.Error(), `field "City": index path [1 3] does not resolve on schema.User: index 3 at position 1 out of range for schema.Address with 1 fields`)
+		assert.Contains(t, err.Error(), `field "Deep": index path [0 0] does not resolve on schema.User: int at position 1 is not a struct`)
+		assert.Contains(t, err.Error(), `field "Negative"`)
+		assert.Contains(t, err.Error(), `field "Flat": index path [7] does not resolve`)
+	})
+
+	t.Run("index and nesting disagree with index path", func(t *testing.T) {
+		type Address struct {
+			City string
+		}
+		type User struct {
+			ID   int
+			Name string
+			Address
+		}
+
+		structMeta, err := NewDefaultMetadata().Parse(User{})
+		require.NoError(t, err)
+
+		clone := structMeta.Clone()
+		clone.Fields[1].Index = 7
+		clone.Fields[2].IsNested = false
+
+		err = clone.Validate()
+		require.Error(t, err)
+		assert.Equal(t, []ValidationRule{ErrInvalidIndexPath, ErrInvalidIndexPath}, fieldErrorRules(err))
+		assert.Contains(t, err.Error(), `field "Name": index 7 does not match index path [1]`)
+		assert.Contains(t, err.Error(), `field "City": IsNested is false but index path [2 0] has 2 elements`)
+	})
+
+	t.Run("same index in different embedded structs", func(t *testing.T) {
+		type Base struct {
+			ID int
+		}
+		type Audit struct {
+			By string
+		}
+		type User struct {
+			Base
+			Audit
+		}
+
+		structMeta, err := NewDefaultMetadata().GetStructMetadata(reflect.TypeOf(User{}))
+		require.NoError(t, err)
+
+		assert.NoError(t, structMeta.Validate())
+	})
+}
+
+func TestStructMetadata_NameAndPkgPath(t *testing.T) {
+	metadata := NewDefaultMetadata()
+
+	named, err := metadata.Parse(&stringUser{})
+	require.NoError(t, err)
+	assert.Equal(t, "stringUser", named.Name)
+	assert.Equal(t, "github.com/talav/schema", named.PkgPath)
+
+	anonymous, err := metadata.Parse(struct{ ID int }{})
+	require.NoError(t, err)
+	assert.Empty(t, anonymous.Name)
+	assert.Empty(t, anonymous.PkgPath)
+
+	manual, err := NewStructMetadata(nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, manual.Name)
+}
+
+type genericUser struct {
+	ID int `schema:"id,pk"`
+}
+
+type genericPage[T any] struct {
+	Items []T `schema:"items"`
+	Total int `schema:"total"`
+	Next  *T  `schema:"next"`
+}
+
+type genericPair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+func TestMetadata_Parse_GenericTypes(t *testing.T) {
+	metadata := NewDefaultMetadata(WithNestedMetadata())
+	userType := reflect.TypeOf(genericUser{})
+
+	page, err := metadata.Parse(genericPage[genericUser]{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "genericPage[github.com/talav/schema.genericUser]", page.Name, "reflect qualifies type arguments")
+	assert.Equal(t, "github.com/talav/schema", page.PkgPath)
+
+	items := page.MustFieldByName("Items")
+	assert.True(t, items.IsSlice)
+	assert.Equal(t, reflect.TypeOf([]genericUser{}), items.Type)
+	assert.Equal(t, userType, items.ElemType)
+	require.NotNil(t, items.ElemMetadata)
+	assert.Equal(t, userType, items.ElemMetadata.Type)
+
+	next := page.MustFieldByName("Next")
+	assert.True(t, next.IsPointer)
+	assert.Equal(t, userType, next.ElemType)
+
+	// Each instantiation is a distinct type with its own metadata
+	other, err := metadata.Parse(genericPage[string]{})
+	require.NoError(t, err)
+	assert.NotSame(t, page, other)
+	assert.Equal(t, reflect.TypeOf(""), other.MustFieldByName("Items").ElemType)
+
+	pair, err := metadata.Parse(genericPair[string, *genericUser]{})
+	require.NoError(t, err)
+	assert.Equal(t, "genericPair[string,*github.com/talav/schema.genericUser]", pair.Name)
+	assert.Equal(t, "value", pair.MustFieldByName("Value").Column)
+	assert.Equal(t, userType, pair.MustFieldByName("Value").ElemType)
+}
+
+func TestMetadata_ParseType(t *testing.T) {
+	type User struct {
+		Name string `schema:"name"`
+	}
+
+	metadata := NewDefaultMetadata()
+
+	t.Run("struct and pointer share metadata", func(t *testing.T) {
+		fromStruct, err := metadata.ParseType(reflect.TypeOf(User{}))
+		require.NoError(t, err)
+
+		fromPointer, err := metadata.ParseType(reflect.TypeOf(&User{}))
+		require.NoError(t, err)
+
+		assert.Same(t, &fromStruct.Fields[0], &fromPointer.Fields[0], "fields are shared")
+		assert.Equal(t, reflect.TypeOf(User{}), fromPointer.Type)
+		assert.False(t, fromStruct.IsPointerRoot)
+		assert.True(t, fromPointer.IsPointerRoot)
+
+		again, err := metadata.ParseType(reflect.TypeOf(&User{}))
+		require.NoError(t, err)
+		assert.Same(t, fromPointer, again, "pointer views are cached")
+
+		twoLevels, err := metadata.ParseType(reflect.TypeOf((**User)(nil)))
+		require.NoError(t, err)
+		assert.Same(t, fromPointer, twoLevels)
+
+		metadata.ClearCache()
+		rebuilt, err := metadata.ParseType(reflect.TypeOf(&User{}))
+		require.NoError(t, err)
+		assert.NotSame(t, fromPointer, rebuilt)
+		assert.True(t, rebuilt.IsPointerRoot)
+	})
+
+	t.Run("non-struct types", func(t *testing.T) {
+		tests := []struct {
+			name string
+			typ  reflect.Type
+			err  string
+		}{
+			{name: "nil", typ: nil, err: "cannot parse nil type"},
+			{name: "int", typ: reflect.TypeOf(0), err: "cannot build struct metadata for int: expected struct type"},
+			{name: "pointer to slice", typ: reflect.TypeOf(&[]User{}), err: "cannot build struct metadata for []schema.User: expected struct type"},
+			{name: "map", typ: reflect.TypeOf(map[string]User{}), err: "cannot build struct metadata for map[string]schema.User: expected struct type"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := metadata.ParseType(tt.typ)
+				assert.Nil(t, result)
+				assert.EqualError(t, err, tt.err)
+			})
+		}
+	})
+}
+
+func TestMetadata_Parse(t *testing.T) {
+	type User struct {
+		Name string `schema:"name"`
+	}
+
+	metadata := NewDefaultMetadata()
+
+	t.Run("value, pointer and typed nil", func(t *testing.T) {
+		expected, err := metadata.ParseType(reflect.TypeOf(User{}))
+		require.NoError(t, err)
+
+		for _, v := range []any{User{}, &User{}, (*User)(nil)} {
+			result, err := metadata.Parse(v)
+			require.NoError(t, err)
+			assert.Same(t, &expected.Fields[0], &result.Fields[0])
+			assert.Equal(t, reflect.TypeOf(v).Kind() == reflect.Pointer, result.IsPointerRoot)
+		}
+	})
+
+	t.Run("nil value", func(t *testing.T) {
+		_, err := metadata.Parse(nil)
+		assert.EqualError(t, err, "cannot parse nil value")
+	})
+
+	t.Run("non-struct value", func(t *testing.T) {
+		_, err := metadata.Parse("text")
+		assert.EqualError(t, err, "cannot build struct metadata for string: expected struct type")
+	})
+}
+
+func TestMetadata_ParseAll(t *testing.T) {
+	type User struct {
+		Name string `schema:"name"`
+	}
+	type Order struct {
+		ID int `schema:"id,pk"`
+	}
+	type Broken struct {
+		Name string `schema:"name,bogus"`
+	}
+
+	metadata := NewDefaultMetadata(WithStrictTags())
+
+	result, err := metadata.ParseAll(User{}, &Order{}, Broken{}, nil, 42, (*User)(nil))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "schema.Broken: failed to build struct metadata:")
+	assert.Contains(t, err.Error(), `unknown tag option "bogus"`)
+	assert.Contains(t, err.Error(), "value 3: cannot parse nil value")
+	assert.Contains(t, err.Error(), "int: cannot build struct metadata for int: expected struct type")
+	assert.Contains(t, fieldErrorRules(err), ErrUnknownOption)
+
+	require.Len(t, result, 2, "successful parses are returned despite failures")
+	for _, typ := range []reflect.Type{reflect.TypeOf(User{}), reflect.TypeOf(Order{})} {
+		cached, err := metadata.ParseType(typ)
+		require.NoError(t, err)
+		assert.Same(t, cached, result[typ], "results are cached")
+	}
+
+	result, err = metadata.ParseAll()
+	require.NoError(t, err)
+	assert.Empty(t, result)
+}
+
+func TestStructMetadata_FieldsSortedByColumn(t *testing.T) {
+	type Base struct {
+		ID int `schema:"id"`
+	}
+	type User struct {
+		Name string `schema:"name"`
+		Base
+		Email   string `schema:"email"`
+		Secret  string `schema:"-"`
+		Age     int    `schema:"age"`
+		Private string `schema:"-"`
+	}
+
+	structMeta, err := NewDefaultMetadata().GetStructMetadata(reflect.TypeOf(User{}))
+	require.NoError(t, err)
+
+	sorted := structMeta.FieldsSortedByColumn()
+
+	names := make([]string, 0, len(sorted))
+	for _, field := range sorted {
+		names = append(names, field.StructFieldName)
+	}
+	assert.Equal(t, []string{"Secret", "Private", "Age", "Email", "ID", "Name"}, names)
+
+	// Original order is left intact
+	assert.Equal(t, "Name", structMeta.Fields[0].StructFieldName)
+	assert.Equal(t, "ID", structMeta.Fields[1].StructFieldName)
+}
+
+func TestStructMetadata_FieldsOrdered(t *testing.T) {
+	type Base struct {
+		ID      int    `schema:"id,order=1"`
+		Version int    `schema:"version"`
+		Tenant  string `schema:"tenant,order=5"`
+	}
+	type User struct {
+		Name string `schema:"name,order=2"`
+		Base
+		Email     string `schema:"email"`
+		Nickname  string `schema:"nickname,order=5"`
+		Age       int    `schema:"age,order=0"`
+		Notes     string `schema:"notes"`
+		Priority  int    `schema:"priority,order=-1"`
+		CreatedAt string `schema:"created_at"`
+	}
+
+	structMeta, err := NewDefaultMetadata().Parse(User{})
+	require.NoError(t, err)
+
+	age := structMeta.MustFieldByName("Age")
+	assert.True(t, age.HasOrder, "order=0 is an explicit position")
+	assert.Equal(t, 0, age.Order)
+	email := structMeta.MustFieldByName("Email")
+	assert.False(t, email.HasOrder)
+
+	ordered := structMeta.FieldsOrdered()
+
+	names := make([]string, 0, len(ordered))
+	for _, field := range ordered {
+		names = append(names, field.StructFieldName)
+	}
+	// Tenant (Index 2) and Nickname (Index 3) tie on order=5
+	assert.Equal(t, []string{"Priority", "Age", "ID", "Name", "Tenant", "Nickname", "Version", "Email", "Notes", "CreatedAt"}, names)
+	assert.Equal(t, "Name", structMeta.Fields[0].StructFieldName, "Fields keeps declaration order")
+
+	t.Run("invalid order", func(t *testing.T) {
+		type Invalid struct {
+			Name string `schema:"name,order=first"`
+		}
+
+		_, err := NewDefaultMetadata().Parse(Invalid{})
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `field Name: invalid order "first": expected an integer`)
+	})
+}
+
+func TestStructMetadata_Range(t *testing.T) {
+	type User struct {
+		ID    int
+		Name  string
+		Email string
+	}
+
+	structMeta, err := NewStructMetadata(reflect.TypeOf(User{}), []FieldMetadata{
+		{StructFieldName: "ID", Type: reflect.TypeOf(0), Index: 0},
+		{StructFieldName: "Name", Type: reflect.TypeOf(""), Index: 1},
+		{StructFieldName: "Email", Type: reflect.TypeOf(""), Index: 2},
+	})
+	require.NoError(t, err)
+
+	t.Run("visits fields in order by pointer", func(t *testing.T) {
+		var names []string
+		structMeta.Range(func(f *FieldMetadata) bool {
+			names = append(names, f.StructFieldName)
+			assert.Same(t, &structMeta.Fields[f.Index], f)
+
+			return true
+		})
+
+		assert.Equal(t, []string{"ID", "Name", "Email"}, names)
+	})
+
+	t.Run("stops early", func(t *testing.T) {
+		visited := 0
+		structMeta.Range(func(f *FieldMetadata) bool {
+			visited++
+
+			return f.StructFieldName != "Name"
+		})
+
+		assert.Equal(t, 2, visited)
+	})
+
+	t.Run("does not allocate", func(t *testing.T) {
+		count := 0
+		allocs := testing.AllocsPerRun(100, func() {
+			structMeta.Range(func(f *FieldMetadata) bool {
+				count += f.Index
+
+				return true
+			})
+		})
+
+		assert.Zero(t, allocs)
+	})
+}
+
+// syntheticFields returns n distinct fields named Field0..FieldN-1 with columns field_0..field_n-1.
+func syntheticFields(n int) []FieldMetadata {
+	fields := make([]FieldMetadata, n)
+	for i := range fields {
+		fields[i] = FieldMetadata{
+			StructFieldName: fmt.Sprintf("Field%d", i),
+			Column:          fmt.Sprintf("field_%d", i),
+			Type:            reflect.TypeOf(0),
+			Index:           i,
+			IndexPath:       []int{i},
+		}
+	}
+
+	return fields
+}
+
+// BenchmarkStructMetadata_Lookup compares linear scans with map indexes for looking up every field
+// by name and by column. Together with BenchmarkStructMetadata_BuildIndexes it justifies
+// indexedLookupMinFields: below it, scans are about as fast as map lookups and skip the map allocations.
+func BenchmarkStructMetadata_Lookup(b *testing.B) {
+	for _, size := range []int{3, 8, 16, 64} {
+		fields := syntheticFields(size)
+
+		for _, mode := range []string{"linear", "indexed"} {
+			structMeta := &StructMetadata{Fields: fields}
+			if mode == "indexed" {
+				structMeta.buildIndexes()
+			}
+
+			b.Run(fmt.Sprintf("fields=%d/%s", size, mode), func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					for i := range fields {
+						if _, ok := structMeta.Field(fields[i].StructFieldName); !ok {
+							b.Fatal("field not found")
+						}
+						if _, ok := structMeta.lookupColumn(fields[i].Column); !ok {
+							b.Fatal("column not found")
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkStructMetadata_BuildIndexes measures the cost the linear mode saves.
+func BenchmarkStructMetadata_BuildIndexes(b *testing.B) {
+	for _, size := range []int{3, 8, 16, 64} {
+		fields := syntheticFields(size)
+
+		b.Run(fmt.Sprintf("fields=%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				structMeta := &StructMetadata{Fields: fields}
+				structMeta.buildIndexes()
+			}
+		})
+	}
+}
+
+func TestStructMetadata_Clone(t *testing.T) {
+	type User struct {
+		ID    int    `schema:"id,pk"`
+		Email string `schema:"email,omitempty"`
+	}
+
+	metadata := NewDefaultMetadata(WithCaseInsensitiveColumns())
+	original, err := metadata.Parse(User{})
+	require.NoError(t, err)
+
+	clone := original.Clone()
+	require.NotSame(t, original, clone)
+	assert.Equal(t, original.String(), clone.String())
+
+	// Mutate the clone's fields, nested slices and maps
+	clone.Fields[0].Column = "user_id"
+	clone.Fields[0].IndexPath[0] = 9
+	clone.Fields[1].Tag.Options[0] = "required"
+	clone.Fields[1].Flags["required"] = true
+	clone.Fields[1].TagMetadata["extra"] = true
+
+	reparsed, err := metadata.Parse(User{})
+	require.NoError(t, err)
+	require.Same(t, original, reparsed)
+
+	id := reparsed.MustFieldByName("ID")
+	assert.Equal(t, "id", id.Column)
+	assert.Equal(t, []int{0}, id.IndexPath)
+
+	email := reparsed.MustFieldByName("Email")
+	assert.Equal(t, []string{"omitempty"}, email.Tag.Options)
+	assert.Equal(t, map[string]bool{"omitempty": true}, email.Flags)
+	assert.NotContains(t, email.TagMetadata, "extra")
+	assert.Len(t, reparsed.Fields, 2)
+
+	// The clone's lookups point into its own fields
+	field, ok := clone.Field("ID")
+	require.True(t, ok)
+	assert.Equal(t, "user_id", field.Column)
+	byColumn, ok := clone.FieldByColumn("EMAIL")
+	require.True(t, ok)
+	assert.Same(t, &clone.Fields[1], clone.fieldsByFoldedColumn["email"])
+	assert.Equal(t, "Email", byColumn.StructFieldName)
+}
+
+func TestStructMetadata_Reindex(t *testing.T) {
+	for _, size := range []int{3, 20} {
+		t.Run(fmt.Sprintf("fields=%d", size), func(t *testing.T) {
+			original, err := NewStructMetadata(reflect.TypeOf(struct{}{}), syntheticFields(size))
+			require.NoError(t, err)
+
+			clone := original.Clone()
+			clone.Fields[1].Column = "renamed"
+			clone.Fields[2].StructFieldName = "Renamed"
+			require.NoError(t, clone.Reindex())
+
+			field, ok := clone.FieldByColumn("renamed")
+			require.True(t, ok)
+			assert.Equal(t, clone.Fields[1].StructFieldName, field.StructFieldName)
+			_, ok = clone.FieldByColumn(original.Fields[1].Column)
+			assert.False(t, ok, "old column no longer resolves")
+			assert.True(t, clone.Has("Renamed"))
+			assert.False(t, clone.Has(original.Fields[2].StructFieldName))
+
+			// Fields may be appended once Reindex is called
+			clone.Fields = append(clone.Fields, FieldMetadata{StructFieldName: "Added", Column: "added", Type: reflect.TypeOf(""), Index: size, IndexPath: []int{size}})
+			require.NoError(t, clone.Reindex())
+			added, ok := clone.Field("Added")
+			require.True(t, ok)
+			assert.Same(t, &clone.Fields[size], added)
+
+			_, ok = original.FieldByColumn("renamed")
+			assert.False(t, ok, "the original is untouched")
+		})
+	}
+
+	t.Run("case-insensitive columns", func(t *testing.T) {
+		type User struct {
+			ID    int    `schema:"id"`
+			Email string `schema:"email"`
+		}
+
+		original, err := NewDefaultMetadata(WithCaseInsensitiveColumns()).Parse(User{})
+		require.NoError(t, err)
+
+		clone := original.Clone()
+		clone.Fields[1].Column = "mail"
+		require.NoError(t, clone.Reindex())
+
+		field, ok := clone.FieldByColumn("MAIL")
+		require.True(t, ok)
+		assert.Equal(t, "Email", field.StructFieldName)
+		_, ok = clone.FieldByColumn("EMAIL")
+		assert.False(t, ok)
+
+		clone.Fields[1].Column = "ID"
+		err = clone.Reindex()
+		assert.Contains(t, fieldErrorRules(err), ErrDuplicateColumn)
+		field, ok = clone.FieldByColumn("Id")
+		require.True(t, ok)
+		assert.Equal(t, "ID", field.StructFieldName, "first field wins on collision")
+	})
+
+	t.Run("recomputes nesting from index paths", func(t *testing.T) {
+		type Address struct {
+			City string
+		}
+		type User struct {
+			Name string
+			Address
+		}
+
+		original, err := NewDefaultMetadata().Parse(User{})
+		require.NoError(t, err)
+
+		clone := original.Clone()
+		clone.Fields[0].IndexPath = []int{1, 0}
+		clone.Fields[1].IndexPath = []int{0}
+		require.NoError(t, clone.Reindex())
+
+		assert.True(t, clone.Fields[0].IsNested)
+		assert.False(t, clone.Fields[1].IsNested)
+		require.NoError(t, clone.Validate())
+		user := User{Name: "Alice", Address: Address{City: "Oslo"}}
+		assert.Equal(t, "Oslo", clone.Fields[0].GetValue(reflect.ValueOf(user)).Interface())
+		assert.Equal(t, "Alice", clone.Fields[1].GetValue(reflect.ValueOf(user)).Interface())
+	})
+}
+
+type walkAddress struct {
+	City string `schema:"city"`
+}
+
+type walkComment struct {
+	Body string `schema:"body"`
+}
+
+type walkPost struct {
+	Title    string        `schema:"title"`
+	Comments []walkComment `schema:"comments"`
+	Author   *walkAuthor   `schema:"author"`
+}
+
+type walkAuthor struct {
+	Name    string      `schema:"name"`
+	Address walkAddress `schema:"address"`
+	Posts   []walkPost  `schema:"posts"`
+	Tags    []string    `schema:"tags"`
+}
+
+func TestStructMetadata_Walk(t *testing.T) {
+	structMeta, err := NewDefaultMetadata(WithNestedMetadata()).Parse(walkAuthor{})
+	require.NoError(t, err)
+
+	t.Run("depth-first with paths", func(t *testing.T) {
+		var visited []string
+		structMeta.Walk(func(path []string, f FieldMetadata) bool {
+			assert.Equal(t, f.StructFieldName, path[len(path)-1])
+			visited = append(visited, strings.Join(path, "."))
+
+			return true
+		})
+
+		assert.Equal(t, []string{
+			"Name",
+			"Address",
+			"Address.City",
+			"Posts",
+			"Posts.Title",
+			"Posts.Comments",
+			"Posts.Comments.Body",
+			"Posts.Author", // refers back to walkAuthor: reported, not descended into
+			"Tags",
+		}, visited)
+	})
+
+	t.Run("early termination", func(t *testing.T) {
+		var visited []string
+		structMeta.Walk(func(path []string, _ FieldMetadata) bool {
+			visited = append(visited, strings.Join(path, "."))
+
+			return len(path) < 2
+		})
+
+		assert.Equal(t, []string{"Name", "Address", "Address.City"}, visited)
+	})
+
+	t.Run("without nested metadata", func(t *testing.T) {
+		flat, err := NewDefaultMetadata().Parse(walkAuthor{})
+		require.NoError(t, err)
+
+		count := 0
+		flat.Walk(func(path []string, _ FieldMetadata) bool {
+			assert.Len(t, path, 1)
+			count++
+
+			return true
+		})
+		assert.Equal(t, 4, count)
+	})
+}
+
+func TestFieldMetadata_Extra(t *testing.T) {
+	type User struct {
+		Email string `schema:"email"`
+	}
+
+	original, err := NewDefaultMetadata().Parse(User{})
+	require.NoError(t, err)
+
+	field, ok := original.Field("Email")
+	require.True(t, ok)
+	assert.Nil(t, field.Extra, "allocated lazily")
+	_, ok = field.GetExtra("rules")
+	assert.False(t, ok)
+
+	field.SetExtra("rules", []string{"email"})
+	field.SetExtra("cost", 3)
+
+	email := original.MustFieldByName("Email")
+	rules, ok := email.GetExtra("rules")
+	require.True(t, ok)
+	assert.Equal(t, []string{"email"}, rules)
+
+	clone := original.Clone()
+	clone.Fields[0].SetExtra("cost", 5)
+	clone.Fields[0].SetExtra("clone-only", true)
+
+	cost, _ := field.GetExtra("cost")
+	assert.Equal(t, 3, cost, "Clone copies the Extra map")
+	_, ok = field.GetExtra("clone-only")
+	assert.False(t, ok)
+	cost, _ = clone.Fields[0].GetExtra("cost")
+	assert.Equal(t, 5, cost)
+}
+
+func TestMetadata_WithImmutable(t *testing.T) {
+	type Address struct {
+		City string `schema:"city"`
+	}
+	type User struct {
+		Email   string  `schema:"email"`
+		Address Address `schema:"address"`
+	}
+
+	frozen, err := NewDefaultMetadata(WithImmutable(), WithNestedMetadata()).Parse(&User{})
+	require.NoError(t, err)
+	assert.True(t, frozen.IsImmutable(), "pointer views keep the flag")
+
+	require.ErrorIs(t, frozen.Reindex(), ErrImmutableMetadata)
+	assert.PanicsWithValue(t, `schema: SetExtra on field "Email" of immutable metadata, Clone it first`, func() {
+		frozen.Fields[0].SetExtra("rules", true)
+	})
+	email := frozen.MustFieldByName("Email")
+	assert.Panics(t, func() { email.SetExtra("rules", true) }, "copies share Extra with the cached field")
+
+	nested := frozen.MustFieldByName("Address").ElemMetadata
+	require.NotNil(t, nested)
+	assert.True(t, nested.IsImmutable())
+
+	clone := frozen.Clone()
+	assert.False(t, clone.IsImmutable())
+	require.NoError(t, clone.Reindex())
+	assert.NotPanics(t, func() { clone.Fields[0].SetExtra("rules", true) })
+	assert.Nil(t, frozen.Fields[0].Extra)
+	assert.True(t, clone.Fields[1].ElemMetadata.IsImmutable(), "nested metadata is shared by Clone")
+
+	mutable, err := NewDefaultMetadata().Parse(User{})
+	require.NoError(t, err)
+	assert.False(t, mutable.IsImmutable())
+	require.NoError(t, mutable.Reindex())
+}
+
+func TestStructMetadata_LenAndHas(t *testing.T) {
+	for _, size := range []int{3, 20} {
+		t.Run(fmt.Sprintf("fields=%d", size), func(t *testing.T) {
+			structMeta, err := NewStructMetadata(reflect.TypeOf(struct{}{}), syntheticFields(size))
+			require.NoError(t, err)
+
+			assert.Equal(t, size, structMeta.Len())
+			assert.True(t, structMeta.Has("Field0"))
+			assert.True(t, structMeta.Has(fmt.Sprintf("Field%d", size-1)))
+			assert.False(t, structMeta.Has("field0"), "case-sensitive")
+			assert.False(t, structMeta.Has("Missing"))
+		})
+	}
+
+	empty := &StructMetadata{}
+	assert.Zero(t, empty.Len())
+	assert.False(t, empty.Has("ID"))
+}
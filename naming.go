@@ -0,0 +1,55 @@
+package schema
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy derives column names from Go struct field names.
+// It is consulted for fields whose tag does not specify an explicit name.
+type NamingStrategy interface {
+	// Column returns the column name for the given struct field name.
+	Column(fieldName string) string
+}
+
+// SnakeCaseNaming converts field names to snake_case (e.g. "UserName" -> "user_name").
+// It is the default naming strategy. Runs of uppercase letters are kept together as acronyms,
+// with the last letter of a run starting the next word when a lowercase letter follows it:
+// "UserID" -> "user_id", "APIKey" -> "api_key", "httpStatus" -> "http_status".
+// A lone trailing "s" pluralizes the acronym instead ("UserIDs" -> "user_ids"), and digits stay
+// attached to the preceding word ("Base64" -> "base64").
+type SnakeCaseNaming struct{}
+
+// Column implements NamingStrategy.
+func (SnakeCaseNaming) Column(fieldName string) string {
+	runes := []rune(fieldName)
+
+	var sb strings.Builder
+	sb.Grow(len(fieldName) + 4)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Start a new word after a lowercase letter or digit, or at the last letter
+			// of an acronym followed by a lowercase letter ("APIKey" -> "api_key")
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(unicode.IsUpper(runes[i-1]) && startsLowerWord(runes, i+1))) {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(unicode.ToLower(r))
+
+			continue
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+// startsLowerWord reports whether runes[i:] begins with a lowercase letter that is not just
+// a plural "s" closing an acronym, as in "IDs" or "URLsByHost".
+func startsLowerWord(runes []rune, i int) bool {
+	if i >= len(runes) || !unicode.IsLower(runes[i]) {
+		return false
+	}
+
+	return runes[i] != 's' || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))
+}
@@ -0,0 +1,52 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnakeCaseNaming_Column(t *testing.T) {
+	tests := []struct {
+		fieldName string
+		want      string
+	}{
+		{fieldName: "Name", want: "name"},
+		{fieldName: "UserName", want: "user_name"},
+		{fieldName: "UserID", want: "user_id"},
+		{fieldName: "ID", want: "id"},
+		{fieldName: "createdAt", want: "created_at"},
+		{fieldName: "already_snake", want: "already_snake"},
+		{fieldName: "", want: ""},
+		// Acronyms
+		{fieldName: "IDs", want: "ids"},
+		{fieldName: "UserIDs", want: "user_ids"},
+		{fieldName: "URLsByHost", want: "urls_by_host"},
+		{fieldName: "HTTPSession", want: "http_session"},
+		{fieldName: "APIKey", want: "api_key"},
+		{fieldName: "HTTPStatus", want: "http_status"},
+		{fieldName: "httpStatus", want: "http_status"},
+		{fieldName: "HTTPServerURL", want: "http_server_url"},
+		{fieldName: "JSONData", want: "json_data"},
+		{fieldName: "URL", want: "url"},
+		{fieldName: "A", want: "a"},
+		{fieldName: "AB", want: "ab"},
+		{fieldName: "ABc", want: "a_bc"},
+		// Digits
+		{fieldName: "Base64", want: "base64"},
+		{fieldName: "Base64Encoded", want: "base64_encoded"},
+		{fieldName: "SHA256Sum", want: "sha256_sum"},
+		{fieldName: "Line2", want: "line2"},
+		{fieldName: "IPv4Address", want: "i_pv4_address"},
+		{fieldName: "OAuth2Token", want: "o_auth2_token"},
+		// Separators and non-ASCII
+		{fieldName: "User_ID", want: "user_id"},
+		{fieldName: "ÜberName", want: "über_name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fieldName, func(t *testing.T) {
+			assert.Equal(t, tt.want, SnakeCaseNaming{}.Column(tt.fieldName))
+		})
+	}
+}
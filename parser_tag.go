@@ -0,0 +1,147 @@
+package schema
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ignoreTagValue is the tag value marking a field as skipped (like encoding/json).
+const ignoreTagValue = "-"
+
+const (
+	tagOptionPrimaryKey      = "primaryKey"
+	tagOptionPrimaryKeyShort = "pk"
+	tagOptionDefault         = "default"
+	tagOptionRequired        = "required"
+	tagOptionPrefix          = "prefix"
+	tagOptionOmitEmpty       = "omitempty"
+	tagOptionReadOnly        = "readonly"
+	tagOptionReadOnlyShort   = "->"
+	tagOptionInline          = "inline"
+	tagOptionSquash          = "squash"
+	tagOptionAlias           = "alias"
+	tagOptionOrder           = "order"
+	tagOptionTransient       = "transient"
+)
+
+// builtinTagOptions are the option keys understood by the package, always known in strict tag mode.
+// They include the parameter options of the schema tag parser (see ParseSchemaTag).
+var builtinTagOptions = []string{
+	optKeyLocation,
+	optKeyStyle,
+	optKeyExplode,
+	tagOptionPrimaryKey,
+	tagOptionPrimaryKeyShort,
+	tagOptionDefault,
+	tagOptionRequired,
+	tagOptionOmitEmpty,
+	tagOptionPrefix,
+	tagOptionReadOnly,
+	tagOptionReadOnlyShort,
+	tagOptionInline,
+	tagOptionSquash,
+	tagOptionAlias,
+	tagOptionOrder,
+	tagOptionTransient,
+}
+
+// FieldTag represents the name and options parsed from a field's struct tag.
+type FieldTag struct {
+	// Name is the first comma-separated segment of the tag (e.g. "column_name").
+	Name string
+	// Options are the remaining non-empty segments in declaration order (e.g. ["omitempty"]).
+	Options []string
+}
+
+// ParseFieldTag parses the struct tag stored under tagKey (e.g. "schema", "db", "json").
+// A missing or empty tag yields a zero FieldTag. The returned ignored flag is true when
+// the tag value is exactly "-", meaning the field should be skipped.
+func ParseFieldTag(field reflect.StructField, tagKey string) (tag FieldTag, ignored bool) {
+	tagValue := field.Tag.Get(tagKey)
+	if tagValue == "" {
+		return FieldTag{}, false
+	}
+
+	if tagValue == ignoreTagValue {
+		return FieldTag{}, true
+	}
+
+	parts := strings.Split(tagValue, ",")
+	tag.Name = strings.TrimSpace(parts[0])
+	for _, part := range parts[1:] {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			tag.Options = append(tag.Options, trimmed)
+		}
+	}
+
+	return tag, false
+}
+
+// HasOption reports whether the tag contains the given option.
+func (t FieldTag) HasOption(option string) bool {
+	return slices.Contains(t.Options, option)
+}
+
+// OptionValue returns the value of a "key=value" option, and whether the option is present.
+func (t FieldTag) OptionValue(key string) (string, bool) {
+	for _, opt := range t.Options {
+		if k, v, found := strings.Cut(opt, "="); found && k == key {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+// OptionValues returns the values of every "key=value" option with the given key, in declaration order,
+// for options that may repeat such as alias=userId,alias=id. It returns nil if the key is absent.
+func (t FieldTag) OptionValues(key string) []string {
+	var values []string
+	for _, opt := range t.Options {
+		if k, v, found := strings.Cut(opt, "="); found && k == key {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+// BoolOption reports whether a boolean option is enabled, either as a bare flag ("required")
+// or as an explicit value ("required=true").
+func (t FieldTag) BoolOption(key string) bool {
+	if t.HasOption(key) {
+		return true
+	}
+	value, ok := t.OptionValue(key)
+
+	return ok && value == optValueTrue
+}
+
+// Classify splits the options into "key=value" pairs and bare flags, e.g. the options of
+// schema:"created_at,default=now(),omitempty,pk" yield {"default": "now()"} and {"omitempty": true, "pk": true}.
+// Values cannot contain commas. A segment with an empty key (such as "=x") is treated as a flag,
+// and when a key repeats the first occurrence wins, matching OptionValue.
+// Both maps are nil when there is nothing of that kind.
+func (t FieldTag) Classify() (options map[string]string, flags map[string]bool) {
+	for _, opt := range t.Options {
+		key, value, found := strings.Cut(opt, "=")
+		if !found || key == "" {
+			if flags == nil {
+				flags = make(map[string]bool, len(t.Options))
+			}
+			flags[opt] = true
+
+			continue
+		}
+
+		if options == nil {
+			options = make(map[string]string, len(t.Options))
+		}
+		if _, exists := options[key]; !exists {
+			options[key] = value
+		}
+	}
+
+	return options, flags
+}
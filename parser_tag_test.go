@@ -0,0 +1,192 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFieldTag(t *testing.T) {
+	tests := []struct {
+		name        string
+		tag         reflect.StructTag
+		tagKey      string
+		want        FieldTag
+		wantIgnored bool
+	}{
+		{
+			name:   "name only",
+			tag:    `schema:"column_name"`,
+			tagKey: "schema",
+			want:   FieldTag{Name: "column_name"},
+		},
+		{
+			name:   "name with options",
+			tag:    `schema:"column_name,omitempty,pk"`,
+			tagKey: "schema",
+			want:   FieldTag{Name: "column_name", Options: []string{"omitempty", "pk"}},
+		},
+		{
+			name:   "options without name",
+			tag:    `schema:",omitempty"`,
+			tagKey: "schema",
+			want:   FieldTag{Options: []string{"omitempty"}},
+		},
+		{
+			name:   "empty segments are dropped",
+			tag:    `schema:"name,,omitempty,"`,
+			tagKey: "schema",
+			want:   FieldTag{Name: "name", Options: []string{"omitempty"}},
+		},
+		{
+			name:   "custom tag key",
+			tag:    `db:"user_id" schema:"id"`,
+			tagKey: "db",
+			want:   FieldTag{Name: "user_id"},
+		},
+		{
+			name:   "missing tag",
+			tag:    `json:"name"`,
+			tagKey: "schema",
+			want:   FieldTag{},
+		},
+		{
+			name:   "empty tag",
+			tag:    `schema:""`,
+			tagKey: "schema",
+			want:   FieldTag{},
+		},
+		{
+			name:        "skip marker",
+			tag:         `schema:"-"`,
+			tagKey:      "schema",
+			want:        FieldTag{},
+			wantIgnored: true,
+		},
+		{
+			name:   "dash with comma is a name",
+			tag:    `schema:"-,"`,
+			tagKey: "schema",
+			want:   FieldTag{Name: "-"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := reflect.StructField{Name: "Field", Type: reflect.TypeOf(""), Tag: tt.tag}
+
+			got, ignored := ParseFieldTag(field, tt.tagKey)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantIgnored, ignored)
+		})
+	}
+}
+
+func TestFieldTag_HasOption(t *testing.T) {
+	tag := FieldTag{Name: "name", Options: []string{"omitempty", "pk"}}
+
+	assert.True(t, tag.HasOption("omitempty"))
+	assert.True(t, tag.HasOption("pk"))
+	assert.False(t, tag.HasOption("name"))
+	assert.False(t, FieldTag{}.HasOption("omitempty"))
+}
+
+func TestFieldTag_OptionValue(t *testing.T) {
+	tag := FieldTag{Name: "port", Options: []string{"omitempty", "default=8080", "empty="}}
+
+	value, ok := tag.OptionValue("default")
+	assert.True(t, ok)
+	assert.Equal(t, "8080", value)
+
+	value, ok = tag.OptionValue("empty")
+	assert.True(t, ok)
+	assert.Empty(t, value)
+
+	_, ok = tag.OptionValue("omitempty")
+	assert.False(t, ok, "bare flags have no value")
+
+	_, ok = tag.OptionValue("missing")
+	assert.False(t, ok)
+}
+
+func TestFieldTag_OptionValues(t *testing.T) {
+	tag := FieldTag{Name: "user_id", Options: []string{"alias=userId", "pk", "alias=id", "default=0"}}
+
+	assert.Equal(t, []string{"userId", "id"}, tag.OptionValues("alias"))
+	assert.Equal(t, []string{"0"}, tag.OptionValues("default"))
+	assert.Nil(t, tag.OptionValues("pk"), "bare flags have no value")
+	assert.Nil(t, tag.OptionValues("missing"))
+}
+
+func TestFieldTag_BoolOption(t *testing.T) {
+	assert.True(t, FieldTag{Options: []string{"required"}}.BoolOption("required"))
+	assert.True(t, FieldTag{Options: []string{"required=true"}}.BoolOption("required"))
+	assert.False(t, FieldTag{Options: []string{"required=false"}}.BoolOption("required"))
+	assert.False(t, FieldTag{Options: []string{"omitempty"}}.BoolOption("required"))
+}
+
+func TestFieldTag_Classify(t *testing.T) {
+	tests := []struct {
+		name        string
+		tag         reflect.StructTag
+		wantName    string
+		wantOptions map[string]string
+		wantFlags   map[string]bool
+	}{
+		{
+			name:        "mixed options and flags",
+			tag:         `schema:"created_at,default=now(),omitempty,pk"`,
+			wantName:    "created_at",
+			wantOptions: map[string]string{"default": "now()"},
+			wantFlags:   map[string]bool{"omitempty": true, "pk": true},
+		},
+		{
+			name:      "empty segments",
+			tag:       `schema:"name,, ,omitempty"`,
+			wantName:  "name",
+			wantFlags: map[string]bool{"omitempty": true},
+		},
+		{
+			name:        "leading comma leaves the name empty",
+			tag:         `schema:",required,default=1"`,
+			wantOptions: map[string]string{"default": "1"},
+			wantFlags:   map[string]bool{"required": true},
+		},
+		{
+			name:     "trailing comma",
+			tag:      `schema:"name,"`,
+			wantName: "name",
+		},
+		{
+			name:        "empty value and repeated key",
+			tag:         `schema:"name,default=,default=2,size=10"`,
+			wantName:    "name",
+			wantOptions: map[string]string{"default": "", "size": "10"},
+		},
+		{
+			name:      "empty key is a flag",
+			tag:       `schema:"name,=x"`,
+			wantName:  "name",
+			wantFlags: map[string]bool{"=x": true},
+		},
+		{
+			name: "no tag",
+			tag:  ``,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := reflect.StructField{Name: "Field", Type: reflect.TypeOf(""), Tag: tt.tag}
+			tag, _ := ParseFieldTag(field, "schema")
+
+			options, flags := tag.Classify()
+
+			assert.Equal(t, tt.wantName, tag.Name)
+			assert.Equal(t, tt.wantOptions, options)
+			assert.Equal(t, tt.wantFlags, flags)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package schema
+
+import "errors"
+
+// CheckRequired verifies that every field marked "required" in its tag is set on v,
+// a struct or pointer to struct. A field is unset when it is zero-valued: nil pointers,
+// empty strings, zero numbers and so on. Each unset field is reported as a *FieldError
+// with rule ErrRequired; the errors are joined. It returns nil if all required fields are set.
+func (m *Metadata) CheckRequired(v any) error {
+	rv, err := resolveStructRoot(v, "check required fields of %s", false)
+	if err != nil {
+		return err
+	}
+
+	structMeta, err := m.GetStructMetadata(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, field := range structMeta.Fields {
+		if !field.Required || field.Ignored || !field.Exported {
+			continue
+		}
+
+		// A nil embedded pointer on the path means the field is unset
+		value, err := rv.FieldByIndexErr(field.IndexPath)
+		if err != nil || value.IsZero() {
+			errs = append(errs, newFieldError(field.StructFieldName, ErrRequired, "required field is not set"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,47 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadata_CheckRequired(t *testing.T) {
+	type SignUp struct {
+		Email    string  `schema:"email,required"`
+		Age      *int    `schema:"age,required=true"`
+		Password string  `schema:"password,required"`
+		Nickname string  `schema:"nickname"`
+		Referrer *string `schema:"referrer,required=false"`
+	}
+
+	metadata := NewDefaultMetadata()
+
+	t.Run("all set", func(t *testing.T) {
+		age := 0
+		err := metadata.CheckRequired(SignUp{Email: "a@example.com", Age: &age, Password: "secret"})
+		assert.NoError(t, err, "a non-nil pointer to a zero value counts as set")
+	})
+
+	t.Run("reports every unset field", func(t *testing.T) {
+		err := metadata.CheckRequired(&SignUp{Password: "secret"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `field "Email": required field is not set`)
+		assert.Contains(t, err.Error(), `field "Age": required field is not set`)
+		assert.NotContains(t, err.Error(), "Password")
+		assert.NotContains(t, err.Error(), "Nickname")
+		assert.NotContains(t, err.Error(), "Referrer")
+		assert.Equal(t, []ValidationRule{ErrRequired, ErrRequired}, fieldErrorRules(err))
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		err := metadata.CheckRequired("not a struct")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "expected struct or pointer to struct")
+
+		err = metadata.CheckRequired((*SignUp)(nil))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot check required fields of nil *schema.SignUp")
+	})
+}
@@ -3,6 +3,7 @@ package schema
 import (
 	"fmt"
 	"maps"
+	"reflect"
 	"strings"
 )
 
@@ -247,3 +248,12 @@ func extractBoolean(options map[string]string, key string, defaultValue bool) bo
 
 	return defaultValue
 }
+
+// derefType walks down pointer types (including **T) to the first non-pointer type.
+func derefType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	return typ
+}
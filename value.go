@@ -0,0 +1,171 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// GetValue returns the field's value within structVal, following IndexPath through embedded structs.
+// structVal must be a struct (or pointer to struct) of the type the metadata was built for; pointer
+// roots are dereferenced. The field value itself is returned as declared (pointers are not dereferenced).
+// It returns the zero reflect.Value if structVal is nil, not a struct, or a nil embedded pointer
+// lies on the index path; use GetValueOrZero to tell these cases apart from a valid value.
+// GetValue, GetValueOrZero, GetValueInit and SetValue have value receivers so that they can be
+// called directly on the FieldMetadata values returned by FieldByName and MustFieldByName.
+func (f FieldMetadata) GetValue(structVal reflect.Value) reflect.Value {
+	value, _ := f.GetValueOrZero(structVal)
+
+	return value
+}
+
+// GetValueOrZero is like GetValue but reports whether the field could be reached. It never panics:
+// ok is false, with the zero reflect.Value, when structVal is nil or not a struct, or when an
+// intermediate embedded pointer on the index path is nil.
+func (f FieldMetadata) GetValueOrZero(structVal reflect.Value) (reflect.Value, bool) {
+	rv, ok := structRoot(structVal)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	if !f.IsNested {
+		return rv.Field(f.Index), true
+	}
+
+	value, err := rv.FieldByIndexErr(f.IndexPath)
+	if err != nil {
+		return reflect.Value{}, false
+	}
+
+	return value, true
+}
+
+// GetValueInit returns the settable field within structVal, allocating nil embedded pointers on the
+// index path so the leaf can be written. structVal must be addressable, typically reflect.ValueOf(&s).
+// Errors name the field, as with SetValue, which is built on it.
+func (f FieldMetadata) GetValueInit(structVal reflect.Value) (reflect.Value, error) {
+	rv, ok := structRoot(structVal)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("field %s: cannot set value on %v: expected non-nil struct or pointer to struct", f.StructFieldName, valueType(structVal))
+	}
+
+	if !rv.CanAddr() {
+		return reflect.Value{}, fmt.Errorf("field %s: cannot set value on unaddressable %v, pass a pointer", f.StructFieldName, rv.Type())
+	}
+
+	if !f.IsNested {
+		target := rv.Field(f.Index)
+		if !target.CanSet() {
+			return reflect.Value{}, fmt.Errorf("field %s: field is not settable", f.StructFieldName)
+		}
+
+		return target, nil
+	}
+
+	target, err := fieldByIndexAlloc(rv, f.IndexPath)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("field %s: %w", f.StructFieldName, err)
+	}
+
+	return target, nil
+}
+
+// SetValue sets the field within structVal to v, following IndexPath and allocating nil embedded pointers
+// (see GetValueInit). structVal must be addressable, typically reflect.ValueOf(&s). v is assigned directly
+// when assignable, otherwise converted between compatible kinds as in FromMap; a nil v sets the zero value.
+// Nothing is allocated if v cannot be converted.
+func (f FieldMetadata) SetValue(structVal reflect.Value, v any) error {
+	coerced, err := coerceValue(v, f.Type)
+	if err != nil {
+		return fmt.Errorf("field %s: %w", f.StructFieldName, err)
+	}
+
+	target, err := f.GetValueInit(structVal)
+	if err != nil {
+		return err
+	}
+	target.Set(coerced)
+
+	return nil
+}
+
+// resolveStructRoot is the shared entry-point check for ToMap, FromMap, ApplyDefaults and CheckRequired.
+// It accepts a struct or a pointer to struct and returns the struct value. When mutable is set the
+// struct is modified, so v must be a non-nil pointer. op describes the operation for error messages,
+// with %s standing for the offending value (e.g. "convert %s to map"), giving errors such as
+// "cannot convert nil *pkg.User to map: expected non-nil pointer to struct".
+func resolveStructRoot(v any, op string, mutable bool) (reflect.Value, error) {
+	fail := func(subject, reason string) (reflect.Value, error) {
+		return reflect.Value{}, fmt.Errorf("cannot %s: %s", fmt.Sprintf(op, subject), reason)
+	}
+
+	expected := "expected struct or pointer to struct"
+	if mutable {
+		expected = "expected non-nil pointer to struct"
+	}
+
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return fail("nil", expected)
+	}
+
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return fail("nil "+rv.Type().String(), "expected non-nil pointer to struct")
+		}
+		rv = rv.Elem()
+	} else if mutable && rv.Kind() == reflect.Struct {
+		return fail(rv.Type().String(), expected+", got struct value")
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return fail(reflect.TypeOf(v).String(), expected)
+	}
+
+	return rv, nil
+}
+
+// structRoot dereferences pointers around rv and reports whether the result is a struct.
+func structRoot(rv reflect.Value) (reflect.Value, bool) {
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return reflect.Value{}, false
+		}
+		rv = rv.Elem()
+	}
+
+	return rv, rv.Kind() == reflect.Struct
+}
+
+// valueType describes the type of rv for error messages.
+func valueType(rv reflect.Value) string {
+	if !rv.IsValid() {
+		return "invalid value"
+	}
+
+	return rv.Type().String()
+}
+
+// IsEmptyValue reports whether v is empty by the rules encoding/json applies to omitempty:
+// false, 0, nil pointers and interfaces, and zero-length arrays, maps, slices and strings.
+// Structs are never empty. The zero reflect.Value is reported as empty.
+func IsEmptyValue(v reflect.Value) bool {
+	//nolint:exhaustive // Remaining kinds are never empty
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
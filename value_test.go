@@ -0,0 +1,462 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldMetadata_GetValue(t *testing.T) {
+	metadata := NewDefaultMetadata()
+	structMeta, err := metadata.Parse(mapperUser{})
+	require.NoError(t, err)
+
+	name := structMeta.MustFieldByName("Name")
+	city := structMeta.MustFieldByName("City")
+
+	t.Run("struct value", func(t *testing.T) {
+		value := name.GetValue(reflect.ValueOf(mapperUser{Name: "Alice"}))
+		assert.Equal(t, "Alice", value.Interface())
+	})
+
+	t.Run("pointer root is dereferenced", func(t *testing.T) {
+		value := structMeta.MustFieldByName("Name").GetValue(reflect.ValueOf(&mapperUser{Name: "Bob"}))
+		assert.Equal(t, "Bob", value.Interface())
+	})
+
+	t.Run("promoted through embedded pointer", func(t *testing.T) {
+		value := city.GetValue(reflect.ValueOf(mapperUser{MapperAddress: &MapperAddress{City: "Oslo"}}))
+		assert.Equal(t, "Oslo", value.Interface())
+	})
+
+	t.Run("nil embedded pointer", func(t *testing.T) {
+		assert.False(t, city.GetValue(reflect.ValueOf(mapperUser{})).IsValid())
+	})
+
+	t.Run("nil root and non-struct", func(t *testing.T) {
+		assert.False(t, name.GetValue(reflect.ValueOf((*mapperUser)(nil))).IsValid())
+		assert.False(t, name.GetValue(reflect.ValueOf(42)).IsValid())
+		assert.False(t, name.GetValue(reflect.Value{}).IsValid())
+	})
+}
+
+func TestFieldMetadata_SetValue(t *testing.T) {
+	metadata := NewDefaultMetadata()
+	structMeta, err := metadata.Parse(mapperUser{})
+	require.NoError(t, err)
+
+	t.Run("sets and converts values", func(t *testing.T) {
+		var user mapperUser
+		rv := reflect.ValueOf(&user)
+
+		id := structMeta.MustFieldByName("ID")
+		age := structMeta.MustFieldByName("Age")
+		require.NoError(t, id.SetValue(rv, float64(7)))
+		require.NoError(t, age.SetValue(rv, 30))
+
+		assert.Equal(t, 7, user.ID)
+		require.NotNil(t, user.Age)
+		assert.Equal(t, 30, *user.Age)
+	})
+
+	t.Run("allocates embedded pointer", func(t *testing.T) {
+		var user mapperUser
+
+		require.NoError(t, structMeta.MustFieldByName("City").SetValue(reflect.ValueOf(&user), "Oslo"))
+
+		require.NotNil(t, user.MapperAddress)
+		assert.Equal(t, "Oslo", user.City)
+	})
+
+	t.Run("nil sets zero value", func(t *testing.T) {
+		user := mapperUser{Name: "Alice"}
+
+		require.NoError(t, structMeta.MustFieldByName("Name").SetValue(reflect.ValueOf(&user), nil))
+
+		assert.Empty(t, user.Name)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		name := structMeta.MustFieldByName("Name")
+		id := structMeta.MustFieldByName("ID")
+
+		tests := []struct {
+			name  string
+			field FieldMetadata
+			root  reflect.Value
+			value any
+			err   string
+		}{
+			{
+				name:  "unaddressable struct",
+				field: name,
+				root:  reflect.ValueOf(mapperUser{}),
+				value: "Alice",
+				err:   "field Name: cannot set value on unaddressable schema.mapperUser, pass a pointer",
+			},
+			{
+				name:  "nil pointer",
+				field: name,
+				root:  reflect.ValueOf((*mapperUser)(nil)),
+				value: "Alice",
+				err:   "field Name: cannot set value on *schema.mapperUser: expected non-nil struct or pointer to struct",
+			},
+			{
+				name:  "incompatible value",
+				field: id,
+				root:  reflect.ValueOf(&mapperUser{}),
+				value: "seven",
+				err:   "field ID: cannot assign string to int",
+			},
+			{
+				name:  "overflow",
+				field: id,
+				root:  reflect.ValueOf(&mapperUser{}),
+				value: 1.5,
+				err:   "field ID: value 1.5 is not an integer, cannot assign to int",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.EqualError(t, tt.field.SetValue(tt.root, tt.value), tt.err)
+			})
+		}
+	})
+
+	t.Run("unexported field", func(t *testing.T) {
+		type withUnexported struct {
+			Name   string `schema:"name"`
+			secret string
+		}
+
+		unexported := NewDefaultMetadata(WithIncludeUnexported())
+		meta, err := unexported.Parse(withUnexported{})
+		require.NoError(t, err)
+
+		secret := meta.MustFieldByName("secret")
+		assert.EqualError(t, secret.SetValue(reflect.ValueOf(&withUnexported{}), "x"), "field secret: field is not settable")
+	})
+}
+
+type ValueInner struct {
+	Code string `schema:"code"`
+}
+
+type ValueMiddle struct {
+	Label string `schema:"label"`
+	*ValueInner
+}
+
+type valueOuter struct {
+	Name string `schema:"name"`
+	*ValueMiddle
+}
+
+func TestFieldMetadata_GetValueOrZero(t *testing.T) {
+	structMeta, err := NewDefaultMetadata().Parse(valueOuter{})
+	require.NoError(t, err)
+	label := structMeta.MustFieldByName("Label")
+	code := structMeta.MustFieldByName("Code")
+
+	t.Run("nil pointer one level up", func(t *testing.T) {
+		value, ok := label.GetValueOrZero(reflect.ValueOf(valueOuter{}))
+		assert.False(t, ok)
+		assert.False(t, value.IsValid())
+	})
+
+	t.Run("nil pointer two levels up", func(t *testing.T) {
+		_, ok := code.GetValueOrZero(reflect.ValueOf(valueOuter{}))
+		assert.False(t, ok)
+
+		_, ok = code.GetValueOrZero(reflect.ValueOf(valueOuter{ValueMiddle: &ValueMiddle{}}))
+		assert.False(t, ok)
+	})
+
+	t.Run("reachable", func(t *testing.T) {
+		outer := valueOuter{ValueMiddle: &ValueMiddle{ValueInner: &ValueInner{Code: "X1"}}}
+		value, ok := code.GetValueOrZero(reflect.ValueOf(&outer))
+		require.True(t, ok)
+		assert.Equal(t, "X1", value.String())
+
+		value, ok = code.GetValueOrZero(reflect.ValueOf(valueOuter{ValueMiddle: &ValueMiddle{ValueInner: &ValueInner{}}}))
+		require.True(t, ok, "zero values are reachable")
+		assert.Empty(t, value.String())
+	})
+
+	t.Run("invalid root", func(t *testing.T) {
+		_, ok := code.GetValueOrZero(reflect.ValueOf((*valueOuter)(nil)))
+		assert.False(t, ok)
+		_, ok = code.GetValueOrZero(reflect.Value{})
+		assert.False(t, ok)
+	})
+}
+
+func TestFieldMetadata_GetValueInit(t *testing.T) {
+	structMeta, err := NewDefaultMetadata().Parse(valueOuter{})
+	require.NoError(t, err)
+	label := structMeta.MustFieldByName("Label")
+	code := structMeta.MustFieldByName("Code")
+
+	t.Run("allocates one level", func(t *testing.T) {
+		var outer valueOuter
+		value, err := label.GetValueInit(reflect.ValueOf(&outer))
+		require.NoError(t, err)
+		value.SetString("primary")
+
+		require.NotNil(t, outer.ValueMiddle)
+		assert.Equal(t, "primary", outer.Label)
+		assert.Nil(t, outer.ValueInner, "only pointers on the path are allocated")
+	})
+
+	t.Run("allocates two levels", func(t *testing.T) {
+		var outer valueOuter
+		value, err := code.GetValueInit(reflect.ValueOf(&outer))
+		require.NoError(t, err)
+		value.SetString("X1")
+
+		require.NotNil(t, outer.ValueMiddle)
+		require.NotNil(t, outer.ValueInner)
+		assert.Equal(t, "X1", outer.Code)
+	})
+
+	t.Run("keeps existing pointers", func(t *testing.T) {
+		middle := &ValueMiddle{Label: "kept"}
+		outer := valueOuter{ValueMiddle: middle}
+		_, err := code.GetValueInit(reflect.ValueOf(&outer))
+		require.NoError(t, err)
+		assert.Same(t, middle, outer.ValueMiddle)
+		assert.Equal(t, "kept", outer.Label)
+	})
+
+	t.Run("SetValue crosses nil pointers", func(t *testing.T) {
+		var outer valueOuter
+		require.NoError(t, code.SetValue(reflect.ValueOf(&outer), "Y2"))
+		assert.Equal(t, "Y2", outer.Code)
+	})
+
+	t.Run("SetValue allocates nothing on conversion errors", func(t *testing.T) {
+		var outer valueOuter
+		require.Error(t, code.SetValue(reflect.ValueOf(&outer), 42))
+		assert.Nil(t, outer.ValueMiddle)
+	})
+
+	t.Run("unaddressable root", func(t *testing.T) {
+		_, err := code.GetValueInit(reflect.ValueOf(valueOuter{}))
+		assert.EqualError(t, err, "field Code: cannot set value on unaddressable schema.valueOuter, pass a pointer")
+	})
+}
+
+func TestFieldMetadata_IsNested(t *testing.T) {
+	structMeta, err := NewDefaultMetadata().Parse(valueOuter{})
+	require.NoError(t, err)
+
+	name := structMeta.MustFieldByName("Name")
+	assert.False(t, name.IsNested)
+	assert.True(t, structMeta.MustFieldByName("Label").IsNested)
+	assert.True(t, structMeta.MustFieldByName("Code").IsNested)
+
+	outer := valueOuter{Name: "root"}
+	assert.Equal(t, "root", name.GetValue(reflect.ValueOf(outer)).String())
+	require.NoError(t, name.SetValue(reflect.ValueOf(&outer), "changed"))
+	assert.Equal(t, "changed", outer.Name)
+
+	manual, err := NewStructMetadata(reflect.TypeOf(valueOuter{}), []FieldMetadata{
+		{StructFieldName: "Name", Index: 0, IndexPath: []int{0}, Type: reflect.TypeOf("")},
+		{StructFieldName: "Label", Index: 0, IndexPath: []int{1, 0}, Type: reflect.TypeOf(""), IsNested: false},
+	})
+	require.NoError(t, err)
+	assert.False(t, manual.Fields[0].IsNested)
+	assert.True(t, manual.Fields[1].IsNested, "NewStructMetadata derives IsNested from IndexPath")
+}
+
+// BenchmarkFieldMetadata_GetValue compares direct Field(Index) access for flat fields with the
+// IndexPath walk. IndexPath forces the walk on the same flat field to isolate its cost.
+func BenchmarkFieldMetadata_GetValue(b *testing.B) {
+	structMeta, err := NewDefaultMetadata().Parse(valueOuter{})
+	require.NoError(b, err)
+
+	name := structMeta.MustFieldByName("Name")
+	walked := name
+	walked.IsNested = true
+	code := structMeta.MustFieldByName("Code")
+
+	outer := valueOuter{Name: "root", ValueMiddle: &ValueMiddle{ValueInner: &ValueInner{Code: "X1"}}}
+	rv := reflect.ValueOf(&outer)
+
+	for _, bench := range []struct {
+		name  string
+		field *FieldMetadata
+	}{
+		{name: "flat/Field", field: &name},
+		{name: "flat/IndexPath", field: &walked},
+		{name: "nested", field: &code},
+	} {
+		b.Run(bench.name+"/GetValue", func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				_ = bench.field.GetValue(rv)
+			}
+		})
+		b.Run(bench.name+"/SetValue", func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				_ = bench.field.SetValue(rv, "value")
+			}
+		})
+	}
+}
+
+type rootAccount struct {
+	ID    int    `schema:"id,required"`
+	Email string `schema:"email,default=user@example.com"`
+}
+
+func TestMetadata_EntryPointsAcceptStructAndPointer(t *testing.T) {
+	metadata := NewDefaultMetadata()
+
+	t.Run("Parse", func(t *testing.T) {
+		fromValue, err := metadata.Parse(rootAccount{})
+		require.NoError(t, err)
+		fromPointer, err := metadata.Parse(&rootAccount{})
+		require.NoError(t, err)
+
+		assert.Same(t, &fromValue.Fields[0], &fromPointer.Fields[0])
+		assert.False(t, fromValue.IsPointerRoot)
+		assert.True(t, fromPointer.IsPointerRoot)
+	})
+
+	t.Run("ToMap", func(t *testing.T) {
+		account := rootAccount{ID: 1, Email: "a@example.com"}
+		expected := map[string]any{"id": 1, "email": "a@example.com"}
+
+		fromValue, err := metadata.ToMap(account)
+		require.NoError(t, err)
+		fromPointer, err := metadata.ToMap(&account)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, fromValue)
+		assert.Equal(t, expected, fromPointer)
+	})
+
+	t.Run("CheckRequired", func(t *testing.T) {
+		assert.NoError(t, metadata.CheckRequired(rootAccount{ID: 1}))
+		assert.NoError(t, metadata.CheckRequired(&rootAccount{ID: 1}))
+		assert.Error(t, metadata.CheckRequired(rootAccount{}))
+		assert.Error(t, metadata.CheckRequired(&rootAccount{}))
+	})
+
+	t.Run("FromMap and ApplyDefaults require a pointer", func(t *testing.T) {
+		var account rootAccount
+		require.NoError(t, metadata.FromMap(map[string]any{"id": 2}, &account))
+		require.NoError(t, metadata.ApplyDefaults(&account))
+		assert.Equal(t, rootAccount{ID: 2, Email: "user@example.com"}, account)
+
+		assert.EqualError(t, metadata.FromMap(map[string]any{"id": 2}, account),
+			"cannot decode map into schema.rootAccount: expected non-nil pointer to struct, got struct value")
+		assert.EqualError(t, metadata.ApplyDefaults(account),
+			"cannot apply defaults to schema.rootAccount: expected non-nil pointer to struct, got struct value")
+	})
+
+	t.Run("invalid roots return errors instead of panicking", func(t *testing.T) {
+		var nilAccount *rootAccount
+		number := 42
+
+		tests := []struct {
+			name string
+			call func() error
+			err  string
+		}{
+			{
+				name: "ToMap nil pointer",
+				call: func() error { _, err := metadata.ToMap(nilAccount); return err },
+				err:  "cannot convert nil *schema.rootAccount to map: expected non-nil pointer to struct",
+			},
+			{
+				name: "ToMap nil",
+				call: func() error { _, err := metadata.ToMap(nil); return err },
+				err:  "cannot convert nil to map: expected struct or pointer to struct",
+			},
+			{
+				name: "ToMap pointer to non-struct",
+				call: func() error { _, err := metadata.ToMap(&number); return err },
+				err:  "cannot convert *int to map: expected struct or pointer to struct",
+			},
+			{
+				name: "FromMap nil pointer",
+				call: func() error { return metadata.FromMap(map[string]any{}, nilAccount) },
+				err:  "cannot decode map into nil *schema.rootAccount: expected non-nil pointer to struct",
+			},
+			{
+				name: "ApplyDefaults non-struct",
+				call: func() error { return metadata.ApplyDefaults(number) },
+				err:  "cannot apply defaults to int: expected non-nil pointer to struct",
+			},
+			{
+				name: "CheckRequired nil pointer",
+				call: func() error { return metadata.CheckRequired(nilAccount) },
+				err:  "cannot check required fields of nil *schema.rootAccount: expected non-nil pointer to struct",
+			},
+			{
+				name: "CheckRequired slice",
+				call: func() error { return metadata.CheckRequired([]rootAccount{}) },
+				err:  "cannot check required fields of []schema.rootAccount: expected struct or pointer to struct",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.EqualError(t, tt.call(), tt.err)
+			})
+		}
+	})
+}
+
+func TestIsEmptyValue(t *testing.T) {
+	zero := 0
+	tests := []struct {
+		name  string
+		value any
+		want  bool
+	}{
+		{name: "empty string", value: "", want: true},
+		{name: "string", value: "a", want: false},
+		{name: "zero int", value: 0, want: true},
+		{name: "int", value: -1, want: false},
+		{name: "zero uint", value: uint8(0), want: true},
+		{name: "uint", value: uint(3), want: false},
+		{name: "zero float", value: 0.0, want: true},
+		{name: "float", value: 0.1, want: false},
+		{name: "false", value: false, want: true},
+		{name: "true", value: true, want: false},
+		{name: "nil slice", value: []int(nil), want: true},
+		{name: "empty slice", value: []int{}, want: true},
+		{name: "slice", value: []int{0}, want: false},
+		{name: "empty map", value: map[string]int{}, want: true},
+		{name: "map", value: map[string]int{"a": 0}, want: false},
+		{name: "empty array", value: [0]int{}, want: true},
+		{name: "array", value: [1]int{}, want: false},
+		{name: "nil pointer", value: (*int)(nil), want: true},
+		{name: "pointer to zero", value: &zero, want: false},
+		{name: "zero struct", value: struct{ A int }{}, want: false},
+		{name: "time", value: time.Time{}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsEmptyValue(reflect.ValueOf(tt.value)))
+		})
+	}
+
+	t.Run("nil interface field", func(t *testing.T) {
+		holder := struct{ V any }{}
+		assert.True(t, IsEmptyValue(reflect.ValueOf(holder).Field(0)))
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		assert.True(t, IsEmptyValue(reflect.Value{}))
+	})
+}